@@ -0,0 +1,210 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+//ErrCircuitOpen is returned without calling Mono while the circuit of an endpoint is open
+var ErrCircuitOpen = errors.New("gomono: circuit open")
+
+//CircuitState is the state of the circuit of an endpoint
+type CircuitState int
+
+const (
+	//CircuitClosed lets every call through
+	CircuitClosed CircuitState = iota
+	//CircuitOpen fails every call fast with ErrCircuitOpen
+	CircuitOpen
+	//CircuitHalfOpen lets a single probe call through to find out whether Mono has recovered
+	CircuitHalfOpen
+)
+
+type (
+	//Thresholds control when the circuit of an endpoint opens and closes again.
+	//Zero values use the defaults.
+	Thresholds struct {
+		//Failures is the number of consecutive failures that opens the circuit. Defaults to 5.
+		Failures int
+		//OpenFor is how long the circuit stays open before a probe call is let through. Defaults to 30s.
+		OpenFor time.Duration
+		//Successes is the number of consecutive successful probes that closes the circuit. Defaults to 1.
+		Successes int
+	}
+
+	//CircuitBreaker fails calls fast while Mono is degraded instead of waiting for every one of them to time out.
+	//Every endpoint has its own circuit.
+	//
+	//Network errors and 5xx responses count as failures. Other Mono errors show that Mono is answering
+	//and count as successes, while calls abandoned through their context are not counted at all.
+	CircuitBreaker struct {
+		Default Thresholds
+		//PerEndpoint thresholds are keyed by Gomono method name, e.g. "Transactions". New rejects unknown names.
+		PerEndpoint map[string]Thresholds
+		//OnStateChange, when set, is called every time the circuit of an endpoint changes state
+		OnStateChange func(endpoint string, from, to CircuitState)
+	}
+
+	breakers struct {
+		cfg      *CircuitBreaker
+		mu       sync.Mutex
+		circuits map[string]*circuit
+	}
+
+	circuit struct {
+		thresholds Thresholds
+		state      CircuitState
+		failures   int
+		successes  int
+		openedAt   time.Time
+		probing    bool
+	}
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+func (c *CircuitBreaker) validate() error {
+	if c == nil {
+		return nil
+	}
+
+	thresholds := []Thresholds{c.Default}
+	for name, t := range c.PerEndpoint {
+		if !knownEndpoint(name) {
+			return fmt.Errorf("gomono: Unknown Circuit Breaker Endpoint %q", name)
+		}
+		thresholds = append(thresholds, t)
+	}
+
+	for _, t := range thresholds {
+		if t.Failures < 0 || t.OpenFor < 0 || t.Successes < 0 {
+			return errors.New("gomono: Circuit Breaker Thresholds Cannot Be Negative")
+		}
+	}
+	return nil
+}
+
+func (t Thresholds) withDefaults() Thresholds {
+	if t.Failures == 0 {
+		t.Failures = 5
+	}
+	if t.OpenFor == 0 {
+		t.OpenFor = 30 * time.Second
+	}
+	if t.Successes == 0 {
+		t.Successes = 1
+	}
+	return t
+}
+
+func newBreakers(cfg *CircuitBreaker) *breakers {
+	if cfg == nil {
+		return nil
+	}
+	return &breakers{cfg: cfg, circuits: make(map[string]*circuit)}
+}
+
+//allow reports whether a call to the endpoint in ctx may be made. When it may, done must be called with its outcome.
+func (b *breakers) allow(ctx context.Context) (done func(status int, err error), err error) {
+	if b == nil {
+		return func(int, error) {}, nil
+	}
+
+	ep, _ := EndpointFromContext(ctx)
+
+	b.mu.Lock()
+	c := b.circuit(ep.Name)
+	from := c.state
+
+	if c.state == CircuitOpen && time.Since(c.openedAt) >= c.thresholds.OpenFor {
+		c.state = CircuitHalfOpen
+		c.successes = 0
+	}
+
+	if c.state == CircuitOpen || (c.state == CircuitHalfOpen && c.probing) {
+		to := c.state
+		b.mu.Unlock()
+		b.notify(ep.Name, from, to)
+		return nil, fmt.Errorf("%w for %v", ErrCircuitOpen, ep.Name)
+	}
+
+	probe := c.state == CircuitHalfOpen
+	if probe {
+		c.probing = true
+	}
+	to := c.state
+	b.mu.Unlock()
+	b.notify(ep.Name, from, to)
+
+	return func(status int, err error) {
+		b.record(ep.Name, c, probe, status, err)
+	}, nil
+}
+
+func (b *breakers) record(name string, c *circuit, probe bool, status int, err error) {
+	b.mu.Lock()
+	from := c.state
+	if probe {
+		c.probing = false
+	}
+
+	switch {
+	case status == 0 && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)):
+		//the caller gave up, which says nothing about Mono
+	case status >= 500 || (status == 0 && err != nil):
+		c.successes = 0
+		c.failures++
+		if c.state == CircuitHalfOpen || (c.state == CircuitClosed && c.failures >= c.thresholds.Failures) {
+			c.state = CircuitOpen
+			c.openedAt = time.Now()
+		}
+	default:
+		c.failures = 0
+		if c.state == CircuitHalfOpen {
+			c.successes++
+			if c.successes >= c.thresholds.Successes {
+				c.state = CircuitClosed
+			}
+		}
+	}
+
+	to := c.state
+	b.mu.Unlock()
+	b.notify(name, from, to)
+}
+
+func (b *breakers) circuit(name string) *circuit {
+	c, ok := b.circuits[name]
+	if !ok {
+		t, ok := b.cfg.PerEndpoint[name]
+		if !ok {
+			t = b.cfg.Default
+		}
+		c = &circuit{thresholds: t.withDefaults()}
+		b.circuits[name] = c
+	}
+	return c
+}
+
+func (b *breakers) notify(name string, from, to CircuitState) {
+	if from != to && b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(name, from, to)
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+//Package cassette records the calls gomono makes to Mono into a fixture file and replays them,
+//so integration tests hit Mono's sandbox once and then run offline.
+//
+//	rec, err := cassette.New("testdata/transactions.json", cassette.ModeAuto, nil)
+//	cfg := gomono.NewDefaultConfig(os.Getenv("MONO_SECRET_KEY"))
+//	cfg.HttpClient = rec.Client()
+//
+//Interactions are matched on method, endpoint template (e.g. "/accounts/{id}/transactions") and query,
+//and replayed in the order they were recorded. The mono-sec-key header is never written to the fixture,
+//neither are the Authorization, Cookie and Set-Cookie headers, and personal data is masked with gomono.Redact.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jcobhams/gomono"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+//Mode tells a Recorder whether to call Mono or replay a fixture
+type Mode int
+
+const (
+	//ModeReplay only replays the fixture and fails calls that were not recorded
+	ModeReplay Mode = iota
+	//ModeRecord calls Mono and overwrites the fixture with the new interactions
+	ModeRecord
+	//ModeAuto replays the fixture when it exists and records it otherwise
+	ModeAuto
+)
+
+const redacted = "[REDACTED]"
+
+//ErrNoInteraction is returned when replaying a call that was not recorded
+var ErrNoInteraction = errors.New("cassette: no recorded interaction")
+
+type (
+	//Recorder is an http.RoundTripper recording or replaying the calls made to Mono
+	Recorder struct {
+		path string
+		mode Mode
+		next http.RoundTripper
+
+		mu           sync.Mutex
+		interactions []Interaction
+		replayed     map[string]int
+	}
+
+	//Interaction is a recorded call, as stored in the fixture file
+	Interaction struct {
+		Request  Request  `json:"request"`
+		Response Response `json:"response"`
+	}
+
+	Request struct {
+		Method string `json:"method"`
+		//Endpoint is the endpoint template, or the path for calls not made by gomono
+		Endpoint string      `json:"endpoint"`
+		Query    string      `json:"query,omitempty"`
+		Header   http.Header `json:"header,omitempty"`
+		Body     string      `json:"body,omitempty"`
+	}
+
+	Response struct {
+		Status int         `json:"status"`
+		Header http.Header `json:"header,omitempty"`
+		Body   string      `json:"body,omitempty"`
+		//BinaryBody holds bodies that are not text, such as PDF statements
+		BinaryBody []byte `json:"binaryBody,omitempty"`
+	}
+
+	fixture struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+)
+
+//New creates a Recorder for the fixture at path. Calls are recorded through next, http.DefaultTransport when nil.
+func New(path string, mode Mode, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	r := &Recorder{path: path, mode: mode, next: next, replayed: make(map[string]int)}
+
+	if mode == ModeAuto {
+		r.mode = ModeReplay
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			r.mode = ModeRecord
+		}
+	}
+
+	if r.mode == ModeReplay {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var f fixture
+		if err := json.Unmarshal(b, &f); err != nil {
+			return nil, fmt.Errorf("cassette: invalid fixture %v: %w", path, err)
+		}
+		r.interactions = f.Interactions
+	}
+
+	return r, nil
+}
+
+//Client returns an http.Client making its calls through the Recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+//Recording reports whether calls are sent to Mono rather than replayed
+func (r *Recorder) Recording() bool {
+	return r.mode == ModeRecord
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := newRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	i := Interaction{Request: recorded, Response: Response{Status: resp.StatusCode, Header: scrubHeader(resp.Header)}}
+	if utf8.Valid(body) {
+		i.Response.Body = scrub(body)
+	} else {
+		i.Response.BinaryBody = body
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, i)
+	return resp, r.save()
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := recorded.key()
+	var matches []Interaction
+	for _, i := range r.interactions {
+		if i.Request.key() == key {
+			matches = append(matches, i)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w for %v", ErrNoInteraction, key)
+	}
+
+	//calls made more often than recorded, such as polls, get the last response again
+	n := r.replayed[key]
+	if n >= len(matches) {
+		n = len(matches) - 1
+	}
+	r.replayed[key]++
+
+	resp := matches[n].Response
+	body := resp.BinaryBody
+	if body == nil {
+		body = []byte(resp.Body)
+	}
+
+	header := resp.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %v", resp.Status, http.StatusText(resp.Status)),
+		StatusCode:    resp.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) save() error {
+	b, err := json.MarshalIndent(fixture{Interactions: r.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(b, '\n'), 0644)
+}
+
+func newRequest(req *http.Request) (Request, error) {
+	recorded := Request{
+		Method:   req.Method,
+		Endpoint: req.URL.Path,
+		Query:    gomono.Redact(req.URL.Query().Encode()),
+		Header:   scrubHeader(req.Header),
+	}
+
+	if ep, ok := gomono.EndpointFromContext(req.Context()); ok {
+		recorded.Endpoint = ep.Template
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return Request{}, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		recorded.Body = scrub(body)
+	}
+
+	return recorded, nil
+}
+
+func (r Request) key() string {
+	return fmt.Sprintf("%v %v?%v", r.Method, r.Endpoint, r.Query)
+}
+
+//scrubHeader masks the secret key, drops credentials and masks personal data in every other header value
+func scrubHeader(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+
+	scrubbed := make(http.Header, len(h))
+	for k, values := range h {
+		switch {
+		case strings.EqualFold(k, "mono-sec-key"):
+			scrubbed[k] = []string{redacted}
+		case strings.EqualFold(k, "Authorization"), strings.EqualFold(k, "Cookie"), strings.EqualFold(k, "Set-Cookie"):
+		default:
+			for _, v := range values {
+				scrubbed[k] = append(scrubbed[k], gomono.Redact(v))
+			}
+		}
+	}
+
+	if len(scrubbed) == 0 {
+		return nil
+	}
+	return scrubbed
+}
+
+//scrub masks personal data in a body. JSON bodies are masked value by value so they stay valid JSON.
+func scrub(body []byte) string {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil || d.More() {
+		return gomono.Redact(string(body))
+	}
+
+	b, err := json.Marshal(scrubValue("", v))
+	if err != nil {
+		return gomono.Redact(string(body))
+	}
+	return string(b)
+}
+
+func scrubValue(key string, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = scrubValue(k, v[k])
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = scrubValue(key, v[i])
+		}
+		return v
+	case string:
+		if piiKey(key) {
+			return redacted
+		}
+		return gomono.Redact(v)
+	case json.Number:
+		if piiKey(key) {
+			return redacted
+		}
+	}
+	return v
+}
+
+//piiKey reports whether gomono.Redact masks the JSON field whatever its value
+func piiKey(key string) bool {
+	field := fmt.Sprintf("%q: \"\"", key)
+	return key != "" && gomono.Redact(field) != field
+}
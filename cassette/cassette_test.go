@@ -0,0 +1,132 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package cassette
+
+import (
+	"context"
+	"errors"
+	"github.com/jcobhams/gomono"
+	"github.com/jcobhams/gomono/gomonotest"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testAccountId = "acc-1"
+
+func newTestServer() *gomonotest.Server {
+	s := gomonotest.NewServer()
+	s.StatementPolls = 1
+	s.AddAccount(gomonotest.Account{
+		Account:  gomono.Account{ID: testAccountId, Name: "ADA OBI", AccountNumber: "0123456789", Balance: gomono.NewMoney(12345678901, "")},
+		Identity: &gomono.IdentityResponse{FirstName: "ADA", BVN: "22222222222", Email: "ada@example.com", PhoneNumber1: "08031234567"},
+	})
+
+	for i := 0; i < 3; i++ {
+		s.AddTransactions(testAccountId, gomono.Transaction{Amount: gomono.NewMoney(10000, ""), Type: "debit"})
+	}
+	return s
+}
+
+func newClient(t *testing.T, rec *Recorder, apiUrl string) gomono.Gomono {
+	g, err := gomono.New(gomono.Config{SecretKey: gomonotest.SecretKey, HttpClient: rec.Client(), ApiUrl: apiUrl})
+	assert.Nil(t, err)
+	return g
+}
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fixture.json")
+
+	s := newTestServer()
+	s.PageSize = 2
+
+	rec, err := New(path, ModeAuto, nil)
+	assert.Nil(t, err)
+	assert.True(t, rec.Recording())
+
+	calls := func(g gomono.Gomono) {
+		info, err := g.Information(testAccountId)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(12345678901), info.Account.Balance.Minor)
+
+		identity, err := g.Identity(testAccountId)
+		assert.Nil(t, err)
+		assert.Equal(t, "ADA", identity.FirstName)
+
+		all, err := g.AllTransactions(testAccountId, time.Time{}, time.Time{}, "", "", 0)
+		assert.Nil(t, err)
+		assert.Len(t, all, 3)
+
+		stmt, err := g.Statement(testAccountId, "", "pdf")
+		assert.Nil(t, err)
+		pdf, err := g.WaitForPdfStatement(context.Background(), testAccountId, stmt.PDF.ID, gomono.PollOptions{Interval: time.Millisecond})
+		assert.Nil(t, err)
+		assert.True(t, pdf.Built())
+
+		_, err = g.Income(testAccountId)
+		assert.True(t, errors.Is(err, gomono.ErrNotFound))
+	}
+
+	calls(newClient(t, rec, s.URL))
+	s.Close()
+
+	b, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	fixture := string(b)
+	for _, secret := range []string{gomonotest.SecretKey, "22222222222", "ada@example.com", "08031234567", "0123456789"} {
+		assert.False(t, strings.Contains(fixture, secret), secret)
+	}
+	assert.True(t, strings.Contains(fixture, "/accounts/{id}/transactions"))
+
+	//the server is gone, everything comes from the fixture
+	rec, err = New(path, ModeAuto, nil)
+	assert.Nil(t, err)
+	assert.False(t, rec.Recording())
+
+	g := newClient(t, rec, s.URL)
+	calls(g)
+
+	_, err = g.CreditTransactions(testAccountId)
+	assert.True(t, errors.Is(err, ErrNoInteraction))
+
+	_, err = New(filepath.Join(dir, "missing.json"), ModeReplay, nil)
+	assert.NotNil(t, err)
+}
+
+func TestScrub(t *testing.T) {
+	assert.Equal(t, `{"amount":12345678901,"bvn":"[REDACTED]","nested":[{"email":"[REDACTED]","note":"call [REDACTED]"}]}`,
+		scrub([]byte(`{"amount": 12345678901, "bvn": 22222222222, "nested": [{"email": "ada@example.com", "note": "call 08031234567"}]}`)))
+	assert.Equal(t, "bvn=[REDACTED]", scrub([]byte("bvn=22222222222")))
+}
+
+func TestScrubHeader(t *testing.T) {
+	h := http.Header{
+		"Mono-Sec-Key":  {"test_sk_secret"},
+		"Authorization": {"Bearer token"},
+		"Cookie":        {"session=abc"},
+		"Set-Cookie":    {"session=abc; HttpOnly"},
+		"X-Customer":    {"ada@example.com"},
+		"Content-Type":  {"application/json"},
+		"Date":          {"Mon, 02 Nov 2020 15:04:05 GMT"},
+	}
+
+	assert.Equal(t, http.Header{
+		"Mono-Sec-Key": {"[REDACTED]"},
+		"X-Customer":   {"[REDACTED]"},
+		"Content-Type": {"application/json"},
+		"Date":         {"Mon, 02 Nov 2020 15:04:05 GMT"},
+	}, scrubHeader(h))
+	assert.Equal(t, "session=abc", h.Get("Cookie"))
+
+	assert.Nil(t, scrubHeader(http.Header{"Cookie": {"session=abc"}}))
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+//DateLayout is the dd-mm-yyyy layout Mono expects for date filters
+const DateLayout = "02-01-2006"
+
+//dateLayouts are the formats Mono uses for dates across its responses
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	DateLayout,
+	"02-Jan-2006",
+	"02/01/2006",
+}
+
+//Date is a date sent by Mono in any of its formats: ISO 8601 timestamps for transactions and statements,
+//dd-mm-yyyy and dd-Mon-yyyy for identity details. Dates without a timezone are read as UTC.
+type Date struct {
+	time.Time
+}
+
+//ParseDate parses a date in any of the formats Mono uses
+func ParseDate(s string) (Date, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Date{Time: t}, nil
+		}
+	}
+	return Date{}, fmt.Errorf("gomono: unsupported date format %q", s)
+}
+
+//MarshalJSON writes the date as an RFC 3339 timestamp, or null when it is not set
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(d.Format(time.RFC3339Nano))), nil
+}
+
+//UnmarshalJSON reads a date in any of the formats Mono uses. Empty strings and null leave the date unset.
+func (d *Date) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if string(b) == "null" {
+		return nil
+	}
+
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return fmt.Errorf("gomono: invalid date %v", string(b))
+	}
+
+	if s == "" {
+		d.Time = time.Time{}
+		return nil
+	}
+
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+//validateDateRange rejects ranges that end on a day before they start. Only the dates are sent to Mono,
+//so the time of day is ignored.
+func validateDateRange(start, end time.Time) error {
+	if !start.IsZero() && !end.IsZero() && day(end).Before(day(start)) {
+		return errors.New("gomono: end date cannot be before start date")
+	}
+	return nil
+}
+
+//day truncates t to the date formatDate sends, in t's own location
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+//formatDate formats a date filter the way Mono expects it, leaving zero times out
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(DateLayout)
+}
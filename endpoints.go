@@ -5,17 +5,72 @@
 package gomono
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
-	"strconv"
 	"strings"
+	"time"
 )
 
+//Endpoints handed to middleware through EndpointFromContext
+var (
+	endpointExchangeToken         = Endpoint{Name: "ExchangeToken", Template: "/account/auth"}
+	endpointInformation           = Endpoint{Name: "Information", Template: "/accounts/{id}"}
+	endpointStatement             = Endpoint{Name: "Statement", Template: "/accounts/{id}/statement"}
+	endpointPdfStatementJobStatus = Endpoint{Name: "PdfStatementJobStatus", Template: "/accounts/{id}/statement/jobs/{jobId}"}
+	endpointDownloadStatement     = Endpoint{Name: "DownloadStatement", Template: "/statements/{file}"}
+	endpointSync                  = Endpoint{Name: "Sync", Template: "/accounts/{id}/sync"}
+	endpointReauthorise           = Endpoint{Name: "Reauthorise", Template: "/accounts/{id}/reauthorise"}
+	endpointUnlink                = Endpoint{Name: "Unlink", Template: "/accounts/{id}/unlink"}
+	endpointTransactions          = Endpoint{Name: "Transactions", Template: "/accounts/{id}/transactions"}
+	endpointCreditTransactions    = Endpoint{Name: "CreditTransactions", Template: "/accounts/{id}/credit"}
+	endpointDebitTransactions     = Endpoint{Name: "DebitTransactions", Template: "/accounts/{id}/debit"}
+	endpointIncome                = Endpoint{Name: "Income", Template: "/accounts/{id}/income"}
+	endpointIdentity              = Endpoint{Name: "Identity", Template: "/accounts/{id}/identity"}
+	endpointInstitutions          = Endpoint{Name: "Institutions", Template: "/coverage"}
+	endpointLookupBVN             = Endpoint{Name: "LookupBVN", Template: "/v1/lookup/bvn/identity"}
+)
+
+//endpoints lists every endpoint, to check the names used as keys in Config
+var endpoints = []Endpoint{
+	endpointExchangeToken,
+	endpointInformation,
+	endpointStatement,
+	endpointPdfStatementJobStatus,
+	endpointDownloadStatement,
+	endpointSync,
+	endpointReauthorise,
+	endpointUnlink,
+	endpointTransactions,
+	endpointCreditTransactions,
+	endpointDebitTransactions,
+	endpointIncome,
+	endpointIdentity,
+	endpointInstitutions,
+	endpointLookupBVN,
+}
+
+//knownEndpoint reports whether name is the Name of an endpoint
+func knownEndpoint(name string) bool {
+	for _, ep := range endpoints {
+		if ep.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 //Auth Endpoints
 
 //ExchangeToken - https://docs.mono.co/reference#authentication-endpoint
 func (g *gomono) ExchangeToken(code string) (string, error) {
+	return g.ExchangeTokenContext(context.Background(), code)
+}
+
+//ExchangeTokenContext - ExchangeToken with a context for cancellation and deadlines
+func (g *gomono) ExchangeTokenContext(ctx context.Context, code string) (string, error) {
 	if code == "" {
 		return "", errors.New("gomono: Code cannot be blank")
 	}
@@ -27,7 +82,7 @@ func (g *gomono) ExchangeToken(code string) (string, error) {
 
 	respTarget := make(map[string]string)
 
-	err = g.makeRequest("POST", fmt.Sprintf("%v/account/auth", g.apiUrl), payload, nil, &respTarget)
+	err = g.makeRequest(ctx, endpointExchangeToken, "POST", fmt.Sprintf("%v/account/auth", g.apiUrl), payload, nil, &respTarget)
 	if err != nil {
 		return "", err
 	}
@@ -38,12 +93,17 @@ func (g *gomono) ExchangeToken(code string) (string, error) {
 
 //Information - https://docs.mono.co/reference#bank-account-details
 func (g *gomono) Information(id string) (*InformationResponse, error) {
+	return g.InformationContext(context.Background(), id)
+}
+
+//InformationContext - Information with a context for cancellation and deadlines
+func (g *gomono) InformationContext(ctx context.Context, id string) (*InformationResponse, error) {
 	if id == "" {
 		return nil, errors.New("gomono: ID is required")
 	}
 
 	var respTarget InformationResponse
-	err := g.makeRequest("GET", fmt.Sprintf("%v/accounts/%v", g.apiUrl, id), nil, nil, &respTarget)
+	err := g.makeRequest(ctx, endpointInformation, "GET", fmt.Sprintf("%v/accounts/%v", g.apiUrl, id), nil, nil, &respTarget)
 	if err != nil {
 		return nil, err
 	}
@@ -53,6 +113,11 @@ func (g *gomono) Information(id string) (*InformationResponse, error) {
 
 //Statement - https://docs.mono.co/reference#bank-statement
 func (g *gomono) Statement(id, period, output string) (*StatementResponse, error) {
+	return g.StatementContext(context.Background(), id, period, output)
+}
+
+//StatementContext - Statement with a context for cancellation and deadlines
+func (g *gomono) StatementContext(ctx context.Context, id, period, output string) (*StatementResponse, error) {
 	if id == "" {
 		return nil, errors.New("gomono: ID is required")
 	}
@@ -78,14 +143,14 @@ func (g *gomono) Statement(id, period, output string) (*StatementResponse, error
 	switch output {
 	case "pdf":
 		var pdfRespTarget StatementResponsePdf
-		err := g.makeRequest("GET", endpoint, nil, nil, &pdfRespTarget)
+		err := g.makeRequest(ctx, endpointStatement, "GET", endpoint, nil, nil, &pdfRespTarget)
 		if err != nil {
 			return nil, err
 		}
 		result.PDF = &pdfRespTarget
 	case "json":
 		var jsonRespTarget StatementResponseJson
-		err := g.makeRequest("POST", endpoint, nil, nil, &jsonRespTarget)
+		err := g.makeRequest(ctx, endpointStatement, "POST", endpoint, nil, nil, &jsonRespTarget)
 		if err != nil {
 			return nil, err
 		}
@@ -97,6 +162,11 @@ func (g *gomono) Statement(id, period, output string) (*StatementResponse, error
 
 //PdfStatementJobStatus - https://docs.mono.co/reference#poll-statement-status
 func (g *gomono) PdfStatementJobStatus(id, jobId string) (*StatementResponsePdf, error) {
+	return g.PdfStatementJobStatusContext(context.Background(), id, jobId)
+}
+
+//PdfStatementJobStatusContext - PdfStatementJobStatus with a context for cancellation and deadlines
+func (g *gomono) PdfStatementJobStatusContext(ctx context.Context, id, jobId string) (*StatementResponsePdf, error) {
 	if id == "" {
 		return nil, errors.New("gomono: ID is required")
 	}
@@ -106,7 +176,7 @@ func (g *gomono) PdfStatementJobStatus(id, jobId string) (*StatementResponsePdf,
 	}
 
 	var respTarget StatementResponsePdf
-	err := g.makeRequest("GET", fmt.Sprintf("%v/accounts/%v/statement/jobs/%v?", g.apiUrl, id, jobId), nil, nil, &respTarget)
+	err := g.makeRequest(ctx, endpointPdfStatementJobStatus, "GET", fmt.Sprintf("%v/accounts/%v/statement/jobs/%v?", g.apiUrl, id, jobId), nil, nil, &respTarget)
 	if err != nil {
 		return nil, err
 	}
@@ -114,35 +184,118 @@ func (g *gomono) PdfStatementJobStatus(id, jobId string) (*StatementResponsePdf,
 	return &respTarget, nil
 }
 
-// User Endpoints
+//Sync - https://docs.mono.co/reference#data-sync
+//
+//An account that needs its user to log in again is not reported as an error. The response
+//has Code set to SyncCodeReauthRequired and, when Mono sends one, the reauth Token.
+func (g *gomono) Sync(id string) (*SyncResponse, error) {
+	return g.SyncContext(context.Background(), id)
+}
 
-//Transactions - https://docs.mono.co/reference#poll-statement-status
-func (g *gomono) Transactions(id, start, end, narration, tnxType string, paginate bool) (*TransactionsResponse, error) {
+//SyncContext - Sync with a context for cancellation and deadlines
+func (g *gomono) SyncContext(ctx context.Context, id string) (*SyncResponse, error) {
 	if id == "" {
 		return nil, errors.New("gomono: ID is required")
 	}
 
-	params := url.Values{}
-	if start != "" {
-		params.Add("start", start)
+	var respTarget SyncResponse
+	err := g.makeRequest(ctx, endpointSync, "POST", fmt.Sprintf("%v/accounts/%v/sync", g.apiUrl, id), nil, nil, &respTarget)
+	if err != nil {
+		var e Error
+		if !errors.Is(err, ErrReauthRequired) || !errors.As(err, &e) {
+			return nil, err
+		}
+
+		//Keep whatever Mono sent (e.g. the reauth token) and normalise the status fields
+		_ = json.Unmarshal([]byte(e.Body), &respTarget)
+		respTarget.Status = SyncStatusFailed
+		respTarget.Code = SyncCodeReauthRequired
+		respTarget.HasNewData = false
+	}
+	return &respTarget, nil
+}
+
+//Reauthorise - https://docs.mono.co/reference#reauth-code
+func (g *gomono) Reauthorise(id string) (*ReauthoriseResponse, error) {
+	return g.ReauthoriseContext(context.Background(), id)
+}
+
+//ReauthoriseContext - Reauthorise with a context for cancellation and deadlines
+func (g *gomono) ReauthoriseContext(ctx context.Context, id string) (*ReauthoriseResponse, error) {
+	if id == "" {
+		return nil, errors.New("gomono: ID is required")
+	}
+
+	var respTarget ReauthoriseResponse
+	err := g.makeRequest(ctx, endpointReauthorise, "POST", fmt.Sprintf("%v/accounts/%v/reauthorise", g.apiUrl, id), nil, nil, &respTarget)
+	if err != nil {
+		return nil, err
+	}
+	return &respTarget, nil
+}
+
+//Unlink - https://docs.mono.co/reference#unlink-account
+//
+//Unlinking an account that is already unlinked fails with an error matching ErrAccountUnlinked.
+func (g *gomono) Unlink(id string) (*UnlinkResponse, error) {
+	return g.UnlinkContext(context.Background(), id)
+}
+
+//UnlinkContext - Unlink with a context for cancellation and deadlines
+func (g *gomono) UnlinkContext(ctx context.Context, id string) (*UnlinkResponse, error) {
+	if id == "" {
+		return nil, errors.New("gomono: ID is required")
 	}
 
-	if end != "" {
-		params.Add("end", end)
+	var respTarget UnlinkResponse
+	err := g.makeRequest(ctx, endpointUnlink, "POST", fmt.Sprintf("%v/accounts/%v/unlink", g.apiUrl, id), nil, nil, &respTarget)
+	if err != nil {
+		return nil, err
 	}
+	return &respTarget, nil
+}
+
+// User Endpoints
+
+//Transactions - https://docs.mono.co/reference#poll-statement-status
+//
+//Zero start or end times leave that bound out of the query.
+func (g *gomono) Transactions(id string, start, end time.Time, narration, tnxType string, paginate bool) (*TransactionsResponse, error) {
+	return g.TransactionsContext(context.Background(), id, start, end, narration, tnxType, paginate)
+}
+
+//TransactionsContext - Transactions with a context for cancellation and deadlines
+func (g *gomono) TransactionsContext(ctx context.Context, id string, start, end time.Time, narration, tnxType string, paginate bool) (*TransactionsResponse, error) {
+	return g.TransactionsWithQueryContext(ctx, id, TransactionsQuery{
+		Start:     start,
+		End:       end,
+		Narration: narration,
+		Type:      TransactionType(tnxType),
+		Paginate:  paginate,
+	})
+}
+
+//TransactionsWithQuery - Transactions filtered by a TransactionsQuery, validated before any call to Mono
+func (g *gomono) TransactionsWithQuery(id string, q TransactionsQuery) (*TransactionsResponse, error) {
+	return g.TransactionsWithQueryContext(context.Background(), id, q)
+}
 
-	if narration != "" {
-		params.Add("narration", narration)
+//TransactionsWithQueryContext - TransactionsWithQuery with a context for cancellation and deadlines
+func (g *gomono) TransactionsWithQueryContext(ctx context.Context, id string, q TransactionsQuery) (*TransactionsResponse, error) {
+	if id == "" {
+		return nil, errors.New("gomono: ID is required")
 	}
 
-	if tnxType != "" {
-		params.Add("type", tnxType)
+	if err := q.Validate(); err != nil {
+		return nil, err
 	}
 
-	params.Add("paginate", strconv.FormatBool(paginate))
+	return g.transactions(ctx, id, q.values())
+}
 
+func (g *gomono) transactions(ctx context.Context, id string, params url.Values) (*TransactionsResponse, error) {
 	var respTarget TransactionsResponse
-	err := g.makeRequest("GET", fmt.Sprintf("%v/accounts/%v/transactions?%v", g.apiUrl, id, params.Encode()), nil, nil, &respTarget)
+	err := g.makeRequest(ctx, endpointTransactions, "GET", fmt.Sprintf("%v/accounts/%v/transactions?%v", g.apiUrl, id, params.Encode()), nil, nil, &respTarget)
 	if err != nil {
 		return nil, err
 	}
@@ -151,21 +304,31 @@ func (g *gomono) Transactions(id, start, end, narration, tnxType string, paginat
 
 //CreditTransactions - https://docs.mono.co/reference#credits
 func (g *gomono) CreditTransactions(id string) (*TransactionByTypeResponse, error) {
-	return g.transactionByType(id, "credit")
+	return g.CreditTransactionsContext(context.Background(), id)
+}
+
+//CreditTransactionsContext - CreditTransactions with a context for cancellation and deadlines
+func (g *gomono) CreditTransactionsContext(ctx context.Context, id string) (*TransactionByTypeResponse, error) {
+	return g.transactionByType(ctx, endpointCreditTransactions, id, "credit")
 }
 
 //DebitTransactions - https://docs.mono.co/reference#debits
 func (g *gomono) DebitTransactions(id string) (*TransactionByTypeResponse, error) {
-	return g.transactionByType(id, "debit")
+	return g.DebitTransactionsContext(context.Background(), id)
 }
 
-func (g *gomono) transactionByType(id, tnxType string) (*TransactionByTypeResponse, error) {
+//DebitTransactionsContext - DebitTransactions with a context for cancellation and deadlines
+func (g *gomono) DebitTransactionsContext(ctx context.Context, id string) (*TransactionByTypeResponse, error) {
+	return g.transactionByType(ctx, endpointDebitTransactions, id, "debit")
+}
+
+func (g *gomono) transactionByType(ctx context.Context, ep Endpoint, id, tnxType string) (*TransactionByTypeResponse, error) {
 	if id == "" {
 		return nil, errors.New("gomono: ID is required")
 	}
 
 	var respTarget TransactionByTypeResponse
-	err := g.makeRequest("GET", fmt.Sprintf("%v/accounts/%v/%v", g.apiUrl, id, tnxType), nil, nil, &respTarget)
+	err := g.makeRequest(ctx, ep, "GET", fmt.Sprintf("%v/accounts/%v/%v", g.apiUrl, id, tnxType), nil, nil, &respTarget)
 	if err != nil {
 		return nil, err
 	}
@@ -174,12 +337,17 @@ func (g *gomono) transactionByType(id, tnxType string) (*TransactionByTypeRespon
 
 //Income - https://docs.mono.co/reference#income
 func (g *gomono) Income(id string) (*IncomeResponse, error) {
+	return g.IncomeContext(context.Background(), id)
+}
+
+//IncomeContext - Income with a context for cancellation and deadlines
+func (g *gomono) IncomeContext(ctx context.Context, id string) (*IncomeResponse, error) {
 	if id == "" {
 		return nil, errors.New("gomono: ID is required")
 	}
 
 	var respTarget IncomeResponse
-	err := g.makeRequest("GET", fmt.Sprintf("%v/accounts/%v/income", g.apiUrl, id), nil, nil, &respTarget)
+	err := g.makeRequest(ctx, endpointIncome, "GET", fmt.Sprintf("%v/accounts/%v/income", g.apiUrl, id), nil, nil, &respTarget)
 	if err != nil {
 		return nil, err
 	}
@@ -188,12 +356,17 @@ func (g *gomono) Income(id string) (*IncomeResponse, error) {
 
 //Identity - https://docs.mono.co/reference#identity
 func (g *gomono) Identity(id string) (*IdentityResponse, error) {
+	return g.IdentityContext(context.Background(), id)
+}
+
+//IdentityContext - Identity with a context for cancellation and deadlines
+func (g *gomono) IdentityContext(ctx context.Context, id string) (*IdentityResponse, error) {
 	if id == "" {
 		return nil, errors.New("gomono: ID is required")
 	}
 
 	var respTarget IdentityResponse
-	err := g.makeRequest("GET", fmt.Sprintf("%v/accounts/%v/identity", g.apiUrl, id), nil, nil, &respTarget)
+	err := g.makeRequest(ctx, endpointIdentity, "GET", fmt.Sprintf("%v/accounts/%v/identity", g.apiUrl, id), nil, nil, &respTarget)
 	if err != nil {
 		return nil, err
 	}
@@ -204,8 +377,13 @@ func (g *gomono) Identity(id string) (*IdentityResponse, error) {
 
 //Institutions - https://docs.mono.co/reference#list-institutions
 func (g *gomono) Institutions() (*InstitutionsResponse, error) {
+	return g.InstitutionsContext(context.Background())
+}
+
+//InstitutionsContext - Institutions with a context for cancellation and deadlines
+func (g *gomono) InstitutionsContext(ctx context.Context) (*InstitutionsResponse, error) {
 	var respTarget []Institution
-	err := g.makeRequest("GET", fmt.Sprintf("%v/coverage", g.apiUrl), nil, nil, &respTarget)
+	err := g.makeRequest(ctx, endpointInstitutions, "GET", fmt.Sprintf("%v/coverage", g.apiUrl), nil, nil, &respTarget)
 	if err != nil {
 		return nil, err
 	}
@@ -215,6 +393,11 @@ func (g *gomono) Institutions() (*InstitutionsResponse, error) {
 }
 
 func (g *gomono) LookupBVN(bvn string) (*IdentityResponse, error) {
+	return g.LookupBVNContext(context.Background(), bvn)
+}
+
+//LookupBVNContext - LookupBVN with a context for cancellation and deadlines
+func (g *gomono) LookupBVNContext(ctx context.Context, bvn string) (*IdentityResponse, error) {
 	if bvn == "" {
 		return nil, errors.New("gomono: BVN is required")
 	}
@@ -225,7 +408,7 @@ func (g *gomono) LookupBVN(bvn string) (*IdentityResponse, error) {
 	}
 
 	var respTarget IdentityResponse
-	err = g.makeRequest("POST", fmt.Sprintf("%v/v1/lookup/bvn/identity", g.apiUrl), payload, nil, &respTarget)
+	err = g.makeRequest(ctx, endpointLookupBVN, "POST", fmt.Sprintf("%v/v1/lookup/bvn/identity", g.apiUrl), payload, nil, &respTarget)
 	if err != nil {
 		return nil, err
 	}
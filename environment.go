@@ -0,0 +1,67 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//Environment is the Mono environment a secret key belongs to
+type Environment string
+
+const (
+	EnvironmentSandbox Environment = "sandbox"
+	EnvironmentLive    Environment = "live"
+)
+
+//Prefixes of Mono secret keys
+const (
+	sandboxKeyPrefix = "test_sk_"
+	liveKeyPrefix    = "live_sk_"
+)
+
+//ErrEnvironmentMismatch is returned by New when the secret key belongs to another environment than Config.Environment
+var ErrEnvironmentMismatch = errors.New("gomono: Secret Key Does Not Match Environment")
+
+func (e Environment) valid() bool {
+	return e == "" || e == EnvironmentSandbox || e == EnvironmentLive
+}
+
+//environmentFromKey detects the environment from the secret key prefix. It is empty for keys without a known prefix.
+func environmentFromKey(secretKey string) Environment {
+	switch {
+	case strings.HasPrefix(secretKey, sandboxKeyPrefix):
+		return EnvironmentSandbox
+	case strings.HasPrefix(secretKey, liveKeyPrefix):
+		return EnvironmentLive
+	}
+	return ""
+}
+
+//resolveEnvironment checks the configured environment against the secret key and fills it in when left empty,
+//so keys of the other environment are also rejected on later calls
+func resolveEnvironment(cfg *Config, secretKey string) error {
+	if !cfg.Environment.valid() {
+		return fmt.Errorf("gomono: Unknown Environment %q", cfg.Environment)
+	}
+
+	detected := environmentFromKey(secretKey)
+	if cfg.Environment != "" && detected != "" && cfg.Environment != detected {
+		return fmt.Errorf("%w: %v key used for the %v environment", ErrEnvironmentMismatch, detected, cfg.Environment)
+	}
+
+	if cfg.Environment == "" {
+		cfg.Environment = detected
+	}
+	return nil
+}
+
+//Environment returns the environment the client runs against. It is empty when Config.Environment was not set
+//and the secret key has no test_sk_ or live_sk_ prefix.
+func (g *gomono) Environment() Environment {
+	return g.environment
+}
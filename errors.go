@@ -0,0 +1,104 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+//Sentinel errors matched by Error through errors.Is
+var (
+	ErrUnauthorized    = errors.New("gomono: unauthorized")
+	ErrNotFound        = errors.New("gomono: not found")
+	ErrReauthRequired  = errors.New("gomono: reauthorisation required")
+	ErrRateLimited     = errors.New("gomono: rate limited")
+	ErrServer          = errors.New("gomono: mono server error")
+	ErrAccountUnlinked = errors.New("gomono: account already unlinked")
+)
+
+//errorPayload is the JSON body Mono sends along with a failed request
+type errorPayload struct {
+	Message string          `json:"message"`
+	Type    string          `json:"type"`
+	Code    json.RawMessage `json:"code"`
+}
+
+//newError builds the Error for a failed response to the request made to endpoint.
+//The URL is passed in because responses built by custom transports or middleware may not carry their Request.
+func newError(endpoint string, resp *http.Response, body []byte) Error {
+	e := Error{
+		Code:       resp.StatusCode,
+		Body:       string(body),
+		Endpoint:   endpoint,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+
+	var p errorPayload
+	if json.Unmarshal(body, &p) == nil {
+		e.Message = p.Message
+		e.Type = p.Type
+
+		//Mono sends the code either as a string or as a number
+		var code string
+		if json.Unmarshal(p.Code, &code) == nil {
+			e.ErrorCode = code
+		} else if len(p.Code) > 0 && string(p.Code) != "null" {
+			e.ErrorCode = string(p.Code)
+		}
+	}
+	return e
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("Request To %v Endpoint Failed With Status Code %v | Body: %v", e.Endpoint, e.Code, e.Body)
+}
+
+//Is reports whether the error matches one of the gomono sentinel errors
+func (e Error) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.Code == http.StatusUnauthorized || e.Code == http.StatusForbidden
+	case ErrNotFound:
+		return e.Code == http.StatusNotFound
+	case ErrReauthRequired:
+		return e.reauthRequired()
+	case ErrRateLimited:
+		return e.Code == http.StatusTooManyRequests
+	case ErrServer:
+		return e.Code >= 500
+	case ErrAccountUnlinked:
+		return e.accountUnlinked()
+	}
+	return false
+}
+
+func (e Error) reauthRequired() bool {
+	for _, v := range []string{e.ErrorCode, e.Type} {
+		if strings.EqualFold(v, "REAUTHORISATION_REQUIRED") {
+			return true
+		}
+	}
+
+	msg := strings.ToLower(e.Message)
+	return strings.Contains(msg, "reauthorisation required") || strings.Contains(msg, "re-authorisation required")
+}
+
+func (e Error) accountUnlinked() bool {
+	if e.Code < 400 || e.Code >= 500 {
+		return false
+	}
+
+	for _, v := range []string{e.ErrorCode, e.Type} {
+		if strings.EqualFold(v, "ACCOUNT_UNLINKED") {
+			return true
+		}
+	}
+	//other messages may mention unlinking without the account being unlinked, e.g. "account cannot be unlinked"
+	return strings.Contains(strings.ToLower(e.Message), "already been unlinked")
+}
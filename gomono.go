@@ -6,6 +6,7 @@ package gomono
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -19,34 +20,94 @@ import (
 type (
 	Gomono interface {
 		ExchangeToken(code string) (string, error)
+		ExchangeTokenContext(ctx context.Context, code string) (string, error)
 		Information(id string) (*InformationResponse, error)
+		InformationContext(ctx context.Context, id string) (*InformationResponse, error)
 		Statement(id, period, output string) (*StatementResponse, error)
+		StatementContext(ctx context.Context, id, period, output string) (*StatementResponse, error)
 		PdfStatementJobStatus(id, jobId string) (*StatementResponsePdf, error)
-		Transactions(id, start, end, narration, tnxType string, paginate bool) (*TransactionsResponse, error)
+		PdfStatementJobStatusContext(ctx context.Context, id, jobId string) (*StatementResponsePdf, error)
+		WaitForPdfStatement(ctx context.Context, id, jobId string, opts PollOptions) (*StatementResponsePdf, error)
+		DownloadStatement(ctx context.Context, pdf *StatementResponsePdf, w io.Writer) (int64, error)
+		DownloadStatementToFile(ctx context.Context, pdf *StatementResponsePdf, path string) error
+		Transactions(id string, start, end time.Time, narration, tnxType string, paginate bool) (*TransactionsResponse, error)
+		TransactionsContext(ctx context.Context, id string, start, end time.Time, narration, tnxType string, paginate bool) (*TransactionsResponse, error)
+		TransactionsWithQuery(id string, q TransactionsQuery) (*TransactionsResponse, error)
+		TransactionsWithQueryContext(ctx context.Context, id string, q TransactionsQuery) (*TransactionsResponse, error)
+		TransactionsIterator(id string, start, end time.Time, narration, tnxType string, maxPages int) *TransactionsIterator
+		TransactionsIteratorContext(ctx context.Context, id string, start, end time.Time, narration, tnxType string, maxPages int) *TransactionsIterator
+		AllTransactions(id string, start, end time.Time, narration, tnxType string, maxPages int) ([]Transaction, error)
+		AllTransactionsContext(ctx context.Context, id string, start, end time.Time, narration, tnxType string, maxPages int) ([]Transaction, error)
 		CreditTransactions(id string) (*TransactionByTypeResponse, error)
+		CreditTransactionsContext(ctx context.Context, id string) (*TransactionByTypeResponse, error)
 		DebitTransactions(id string) (*TransactionByTypeResponse, error)
+		DebitTransactionsContext(ctx context.Context, id string) (*TransactionByTypeResponse, error)
 		Income(id string) (*IncomeResponse, error)
+		IncomeContext(ctx context.Context, id string) (*IncomeResponse, error)
 		Identity(id string) (*IdentityResponse, error)
+		IdentityContext(ctx context.Context, id string) (*IdentityResponse, error)
 		Institutions() (*InstitutionsResponse, error)
+		InstitutionsContext(ctx context.Context) (*InstitutionsResponse, error)
 		LookupBVN(bvn string) (*IdentityResponse, error)
+		LookupBVNContext(ctx context.Context, bvn string) (*IdentityResponse, error)
+		Sync(id string) (*SyncResponse, error)
+		SyncContext(ctx context.Context, id string) (*SyncResponse, error)
+		Reauthorise(id string) (*ReauthoriseResponse, error)
+		ReauthoriseContext(ctx context.Context, id string) (*ReauthoriseResponse, error)
+		Unlink(id string) (*UnlinkResponse, error)
+		UnlinkContext(ctx context.Context, id string) (*UnlinkResponse, error)
+		Environment() Environment
 	}
 
 	gomono struct {
-		secretKey string
-		client    *http.Client
-		apiUrl    string
+		keys        SecretKeyProvider
+		client      *http.Client
+		apiUrl      string
+		retry       RetryPolicy
+		roundTrip   RoundTripFunc
+		tracer      Tracer
+		metrics     MetricsCollector
+		limiter     *rateLimiter
+		breakers    *breakers
+		environment Environment
 	}
 
 	Error struct {
 		Code     int
 		Body     string
 		Endpoint string
+		//Message, Type and ErrorCode are parsed from Mono's JSON error payload when present
+		Message   string
+		Type      string
+		ErrorCode string
+		//RetryAfter is the wait requested by Mono through the Retry-After header, if any
+		RetryAfter time.Duration
 	}
 
 	Config struct {
-		SecretKey  string
-		HttpClient *http.Client
-		ApiUrl     string
+		SecretKey string
+		//SecretKeyProvider, when set instead of SecretKey, supplies the secret key for every call.
+		//New fetches a key from it once to detect the Environment.
+		SecretKeyProvider SecretKeyProvider
+		HttpClient        *http.Client
+		ApiUrl            string
+		//Environment is the Mono environment expected. When set, secret keys of the other environment are rejected.
+		//It is detected from the secret key prefix (test_sk_ or live_sk_) when left empty.
+		Environment Environment
+		//Retry controls automatic retries of failed calls. The zero value makes a single attempt.
+		Retry RetryPolicy
+		//Middleware wraps every HTTP call made to Mono, the first one being the outermost
+		Middleware []Middleware
+		//Logger, when set, logs every HTTP call made to Mono with personal data redacted
+		Logger Logger
+		//Tracer, when set, records a span for every Gomono call
+		Tracer Tracer
+		//Metrics, when set, records every HTTP call made to Mono
+		Metrics MetricsCollector
+		//RateLimit, when set, throttles the calls made to Mono
+		RateLimit *RateLimit
+		//CircuitBreaker, when set, fails calls fast while Mono is degraded
+		CircuitBreaker *CircuitBreaker
 	}
 
 	header struct {
@@ -60,11 +121,27 @@ func New(cfg Config) (Gomono, error) {
 		return nil, err
 	}
 
+	if cfg.SecretKeyProvider == nil {
+		cfg.SecretKeyProvider = staticKey(cfg.SecretKey)
+	}
+
 	g := &gomono{
-		secretKey: cfg.SecretKey,
-		client:    cfg.HttpClient,
-		apiUrl:    cfg.ApiUrl,
+		keys:        cfg.SecretKeyProvider,
+		client:      cfg.HttpClient,
+		apiUrl:      cfg.ApiUrl,
+		retry:       cfg.Retry,
+		tracer:      cfg.Tracer,
+		metrics:     cfg.Metrics,
+		limiter:     newRateLimiter(cfg.RateLimit),
+		breakers:    newBreakers(cfg.CircuitBreaker),
+		environment: cfg.Environment,
+	}
+
+	middleware := append([]Middleware{}, cfg.Middleware...)
+	if cfg.Logger != nil {
+		middleware = append(middleware, logging(cfg.Logger))
 	}
+	g.roundTrip = chain(middleware, g.client.Do)
 
 	return g, nil
 }
@@ -76,14 +153,32 @@ func NewDefaultConfig(secretKey string) Config {
 			Timeout: 5 * time.Second,
 		},
 		ApiUrl: "https://api.withmono.com",
+		Retry:  DefaultRetryPolicy(),
 	}
 }
 
 func validateConfig(cfg *Config) error {
-	if cfg.SecretKey == "" {
+	if cfg.SecretKey == "" && cfg.SecretKeyProvider == nil {
 		return errors.New("gomono: Missing Secret Key")
 	}
 
+	if cfg.SecretKey != "" && cfg.SecretKeyProvider != nil {
+		return errors.New("gomono: Set Either SecretKey Or SecretKeyProvider")
+	}
+
+	secretKey := cfg.SecretKey
+	if cfg.SecretKeyProvider != nil {
+		key, err := cfg.SecretKeyProvider.SecretKey(context.Background())
+		if err != nil {
+			return fmt.Errorf("gomono: secret key unavailable: %w", err)
+		}
+		secretKey = key
+	}
+
+	if err := resolveEnvironment(cfg, secretKey); err != nil {
+		return err
+	}
+
 	if cfg.HttpClient == nil {
 		return errors.New("gomono: HTTP Client Cannot Be Nil")
 	}
@@ -92,61 +187,161 @@ func validateConfig(cfg *Config) error {
 		return errors.New("gomono: Missing API Url")
 	}
 
+	if err := cfg.Retry.validate(); err != nil {
+		return err
+	}
+
+	if err := cfg.RateLimit.validate(); err != nil {
+		return err
+	}
+
+	if err := cfg.CircuitBreaker.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func (g *gomono) preparePayload(body interface{}) (io.Reader, error) {
-	b, err := json.Marshal(body)
-	if err != nil {
-		return nil, err
-	}
-	return bytes.NewReader(b), nil
+func (g *gomono) preparePayload(body interface{}) ([]byte, error) {
+	return json.Marshal(body)
 }
 
-func (g *gomono) makeRequest(method, url string, body io.Reader, headers []header, responseTarget interface{}) error {
+func (g *gomono) makeRequest(ctx context.Context, ep Endpoint, method, url string, body []byte, headers []header, responseTarget interface{}) error {
 	if reflect.TypeOf(responseTarget).Kind() != reflect.Ptr {
 		return errors.New("gomono: responseTarget must be a pointer to a struct for JSON unmarshalling")
 	}
 
-	req, err := http.NewRequest(method, url, body)
+	ctx = withEndpoint(ctx, ep)
+	ctx, span := g.startSpan(ctx, ep, method, url)
+	defer span.End()
+
+	status, retries, err := g.requestWithRetries(ctx, method, url, true, func(ctx context.Context, key string) (int, bool, int, error) {
+		return g.sendRequest(ctx, key, method, url, body, headers, responseTarget)
+	})
+	endSpan(span, status, retries, err)
+	return err
+}
+
+//attemptFunc sends a single attempt at a request with the secret key. It returns the status code received, if any,
+//whether a failed attempt may be retried and the number of bytes read.
+type attemptFunc func(ctx context.Context, key string) (int, bool, int, error)
+
+//requestWithRetries makes the request, retrying it as allowed by the retry policy. The secret key is only
+//fetched, and refreshed when rejected, when withKey is set.
+//It returns the last status code received and the number of retries made.
+func (g *gomono) requestWithRetries(ctx context.Context, method, url string, withKey bool, send attemptFunc) (int, int, error) {
+	attempts := 1
+	if g.retry.allowsMethod(method) {
+		attempts = g.retry.MaxAttempts
+	}
+
+	refreshed := false
+	for attempt := 1; ; attempt++ {
+		var key string
+		if withKey {
+			var err error
+			if key, err = g.secretKey(ctx); err != nil {
+				return 0, attempt - 1, err
+			}
+		}
+
+		status, retry, err := g.doRequest(ctx, method, url, key, send)
+
+		//a rejected key is refreshed and the call made once more with the new key, whatever the retry policy
+		if withKey && status == http.StatusUnauthorized && !refreshed && g.refreshSecretKey(ctx, key) {
+			refreshed = true
+			attempts++
+			g.observeRetry(ctx)
+			continue
+		}
+
+		if err == nil || !retry || attempt >= attempts {
+			return status, attempt - 1, err
+		}
+
+		delay, ok := g.retry.delay(attempt, err)
+		if !ok {
+			return status, attempt - 1, err
+		}
+		g.observeRetry(ctx)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return status, attempt - 1, fmt.Errorf("gomono: request to %v aborted: %w", url, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+//doRequest makes a single attempt at the request through the circuit breaker and rate limiter.
+//It returns the status code received, if any, and whether a failed attempt may be retried.
+func (g *gomono) doRequest(ctx context.Context, method, url, key string, send attemptFunc) (int, bool, error) {
+	done, err := g.breakers.allow(ctx)
 	if err != nil {
-		return err
+		return 0, false, err
+	}
+
+	if err := g.limiter.wait(ctx); err != nil {
+		err = fmt.Errorf("gomono: request to %v aborted while rate limited: %w", url, err)
+		done(0, err)
+		return 0, false, err
+	}
+
+	start := time.Now()
+	status, retry, n, err := send(ctx, key)
+	g.observeRequest(ctx, method, status, err, time.Since(start), n)
+	done(status, err)
+	return status, retry, err
+}
+
+//sendRequest sends the request and decodes the response, also returning the number of bytes read
+func (g *gomono) sendRequest(ctx context.Context, key, method, url string, body []byte, headers []header, responseTarget interface{}) (int, bool, int, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return 0, false, 0, err
 	}
 
 	for _, h := range headers {
 		req.Header.Set(h.Key, h.Value)
 	}
-	req.Header.Set("mono-sec-key", g.secretKey)
+	req.Header.Set("mono-sec-key", key)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Client-Lib", "GoMono | v1 | github.com/jcobhams/gomono")
 
-	resp, err := g.client.Do(req)
+	resp, err := g.roundTrip(req)
 	if err != nil {
-		return err
+		//Surface cancellation and deadline errors as-is so callers can match them with errors.Is
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return 0, false, 0, fmt.Errorf("gomono: request to %v aborted: %w", req.URL.String(), ctxErr)
+		}
+		return 0, true, 0, err
 	}
 	defer resp.Body.Close()
+	g.limiter.adapt(ctx, resp)
 
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return resp.StatusCode, false, len(b), fmt.Errorf("gomono: request to %v aborted: %w", req.URL.String(), ctxErr)
+		}
+		return resp.StatusCode, true, len(b), err
 	}
 
 	if resp.StatusCode == 200 || resp.StatusCode == 201 {
 		err = json.Unmarshal(b, responseTarget)
 		if err != nil {
-			return err
+			return resp.StatusCode, false, len(b), err
 		}
-		return nil
+		return resp.StatusCode, false, len(b), nil
 	}
 
-	err = Error{
-		Code:     resp.StatusCode,
-		Body:     string(b),
-		Endpoint: req.URL.String(),
-	}
-	return err
-}
-
-func (e Error) Error() string {
-	return fmt.Sprintf("Request To %v Endpoint Failed With Status Code %v | Body: %v", e.Endpoint, e.Code, e.Body)
+	err = newError(req.URL.String(), resp, b)
+	return resp.StatusCode, g.retry.retryableStatus(resp.StatusCode), len(b), err
 }
@@ -5,11 +5,21 @@
 package gomono
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -19,6 +29,17 @@ var (
 	testJobId           = "MvRh2vWwv5CGafudTivY"
 	testSecretKey       = "TEST_SECRET_KEY"
 	testMonoConnectCode = "TEST_MONO_CONNECT_CODE"
+	testMissingId       = "5fc68b964bdcbe4eb164e000"
+	testReauthId        = "5fc68b964bdcbe4eb164e111"
+	testReauthToken     = "qNl0GNpT7qU5V9SmlA0W"
+	testUnlinkedId      = "5fc68b964bdcbe4eb164e222"
+	testPagedId         = "5fc68b964bdcbe4eb164e333"
+	testBuildingJobId   = "Qw7dE0pZx1LyUo4rBkNa"
+	testFailedJobId     = "Zr8TgH2sVb6mJc3nXkPq"
+	buildingJobPolls    int32
+	testPdfBody         = "%PDF-1.4 test statement"
+	testStart           = time.Date(2020, time.October, 1, 0, 0, 0, 0, time.UTC)
+	testEnd             = time.Date(2020, time.October, 7, 0, 0, 0, 0, time.UTC)
 	mockServer          *httptest.Server
 	client              Gomono
 )
@@ -31,9 +52,11 @@ func TestMain(m *testing.M) {
 		HttpClient: &http.Client{Timeout: 1 * time.Second},
 		ApiUrl:     mockServer.URL,
 	})
+
+	code := m.Run()
 	mockServer.Close()
 
-	os.Exit(m.Run())
+	os.Exit(code)
 }
 
 func TestNew(t *testing.T) {
@@ -46,6 +69,36 @@ func TestNew(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestNew_Environment(t *testing.T) {
+	g, err := New(NewDefaultConfig(testSecretKey))
+	assert.Nil(t, err)
+	assert.Equal(t, Environment(""), g.Environment())
+
+	g, err = New(NewDefaultConfig("test_sk_abc"))
+	assert.Nil(t, err)
+	assert.Equal(t, EnvironmentSandbox, g.Environment())
+
+	g, err = New(NewDefaultConfig("live_sk_abc"))
+	assert.Nil(t, err)
+	assert.Equal(t, EnvironmentLive, g.Environment())
+
+	cfg := NewDefaultConfig("live_sk_abc")
+	cfg.Environment = EnvironmentSandbox
+	g, err = New(cfg)
+	assert.Nil(t, g)
+	assert.True(t, errors.Is(err, ErrEnvironmentMismatch))
+
+	cfg = NewDefaultConfig(testSecretKey)
+	cfg.Environment = EnvironmentLive
+	g, err = New(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, EnvironmentLive, g.Environment())
+
+	cfg.Environment = "staging"
+	_, err = New(cfg)
+	assert.NotNil(t, err)
+}
+
 func TestGomono_ExchangeToken(t *testing.T) {
 	id, err := client.ExchangeToken("")
 	assert.Empty(t, id)
@@ -65,6 +118,8 @@ func TestGomono_Information(t *testing.T) {
 	r, err = client.Information(testAccountId)
 	assert.NotNil(t, r)
 	assert.Equal(t, testAccountId, r.Account.ID)
+	assert.Equal(t, NewMoney(37836709, "NGN"), r.Account.Balance)
+	assert.Equal(t, InstitutionRef{Name: "Access Bank", BankCode: "044", Type: "PERSONAL_BANKING"}, r.Account.Institution)
 	assert.Nil(t, err)
 }
 
@@ -77,12 +132,13 @@ func TestGomono_Statement(t *testing.T) {
 	assert.NotNil(t, r)
 	assert.Equal(t, 2, r.JSON.Meta.Count)
 	assert.Equal(t, 2, len(r.JSON.Data))
+	assert.Equal(t, "5fc686f98b97632dbef0f8db", r.JSON.Data[0].ID)
 	assert.Nil(t, r.PDF)
 	assert.Nil(t, err)
 
 	r, err = client.Statement(testAccountId, "", "pdf")
 	assert.NotNil(t, r)
-	assert.Equal(t, "BUILDING", r.PDF.Status)
+	assert.Equal(t, StatementStatusBuilding, r.PDF.Status)
 	assert.NotEmpty(t, r.PDF.ID)
 	assert.NotEmpty(t, r.PDF.Path)
 	assert.Nil(t, r.JSON)
@@ -93,29 +149,193 @@ func TestGomono_PdfStatementJobStatus(t *testing.T) {
 	r, err := client.PdfStatementJobStatus(testAccountId, testJobId)
 	assert.NotNil(t, r)
 	assert.Equal(t, testJobId, r.ID)
-	assert.Equal(t, "COMPLETE", r.Status)
+	assert.Equal(t, StatementStatusComplete, r.Status)
+	assert.Nil(t, err)
+}
+
+func TestGomono_WaitForPdfStatement(t *testing.T) {
+	opts := PollOptions{Interval: time.Millisecond, MaxInterval: 5 * time.Millisecond, Multiplier: 2}
+
+	r, err := client.WaitForPdfStatement(context.Background(), testAccountId, testBuildingJobId, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, StatementStatusBuilt, r.Status)
+	assert.NotEmpty(t, r.Path)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&buildingJobPolls))
+
+	r, err = client.WaitForPdfStatement(context.Background(), testAccountId, testFailedJobId, opts)
+	assert.True(t, errors.Is(err, ErrStatementFailed))
+	assert.True(t, r.Failed())
+
+	atomic.StoreInt32(&buildingJobPolls, -100)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	r, err = client.WaitForPdfStatement(ctx, testAccountId, testBuildingJobId, opts)
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	r, err = client.WaitForPdfStatement(context.Background(), testAccountId, "", opts)
+	assert.Nil(t, r)
+	assert.NotNil(t, err)
+}
+
+func TestGomono_DownloadStatement(t *testing.T) {
+	pdf := &StatementResponsePdf{ID: testJobId, Status: StatementStatusBuilt, Path: mockServer.URL + "/statements/pvLhFR89Id2zrnPGJZcM.pdf"}
+
+	var buf bytes.Buffer
+	n, err := client.DownloadStatement(context.Background(), pdf, &buf)
 	assert.Nil(t, err)
+	assert.Equal(t, int64(len(testPdfBody)), n)
+	assert.Equal(t, testPdfBody, buf.String())
+
+	dir, _ := ioutil.TempDir("", "gomono")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "statement.pdf")
+	assert.Nil(t, client.DownloadStatementToFile(context.Background(), pdf, path))
+	b, _ := ioutil.ReadFile(path)
+	assert.Equal(t, testPdfBody, string(b))
+
+	html := &StatementResponsePdf{Status: StatementStatusBuilt, Path: mockServer.URL + "/statements/html.pdf"}
+	_, err = client.DownloadStatement(context.Background(), html, &buf)
+	assert.NotNil(t, err)
+
+	err = client.DownloadStatementToFile(context.Background(), html, filepath.Join(dir, "html.pdf"))
+	assert.NotNil(t, err)
+	files, _ := ioutil.ReadDir(dir)
+	assert.Equal(t, 1, len(files))
+
+	missing := &StatementResponsePdf{Status: StatementStatusBuilt, Path: mockServer.URL + "/statements/missing.pdf"}
+	_, err = client.DownloadStatement(context.Background(), missing, &buf)
+	assert.True(t, errors.Is(err, ErrServer))
+
+	_, err = client.DownloadStatement(context.Background(), &StatementResponsePdf{Status: StatementStatusBuilding, Path: pdf.Path}, &buf)
+	assert.NotNil(t, err)
+
+	_, err = client.DownloadStatement(context.Background(), nil, &buf)
+	assert.NotNil(t, err)
 }
 
 func TestGomono_Transactions(t *testing.T) {
-	r, err := client.Transactions(testAccountId, "01-10-2020", "07-10-2020", "test", "debit", true)
+	r, err := client.Transactions(testAccountId, testStart, testEnd, "test", "debit", true)
 	assert.NotNil(t, r)
 	assert.Equal(t, 190, r.Paging.Total)
 	assert.Equal(t, 2, r.Paging.Page)
 	assert.Equal(t, 2, len(r.Data))
+	assert.Equal(t, time.Date(2020, time.July, 21, 0, 0, 0, 0, time.UTC), r.Data[0].Date.Time)
+	assert.Nil(t, err)
+
+	r, err = client.Transactions(testAccountId, testEnd, testStart, "", "", false)
+	assert.Nil(t, r)
+	assert.NotNil(t, err)
+}
+
+func TestGomono_TransactionsWithQuery(t *testing.T) {
+	r, err := client.TransactionsWithQuery(testAccountId, TransactionsQuery{
+		Start:     testStart,
+		End:       testEnd,
+		Narration: "test",
+		Type:      TransactionTypeDebit,
+		Page:      2,
+	})
+	assert.NotNil(t, r)
+	assert.Equal(t, 2, r.Paging.Page)
+	assert.Nil(t, err)
+
+	invalid := []TransactionsQuery{
+		{Start: testEnd, End: testStart},
+		{Type: "refund"},
+		{Page: -1},
+		{Limit: -5},
+	}
+	for _, q := range invalid {
+		r, err = client.TransactionsWithQuery(testAccountId, q)
+		assert.Nil(t, r)
+		assert.True(t, errors.Is(err, ErrInvalidQuery))
+	}
+
+	r, err = client.Transactions(testAccountId, testStart, testEnd, "", "refund", false)
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, ErrInvalidQuery))
+
+	sameDay := TransactionsQuery{Start: testStart.Add(15 * time.Hour), End: testStart.Add(14 * time.Hour)}
+	assert.Nil(t, sameDay.Validate())
+
+	r, err = client.Transactions(testAccountId, testStart, testEnd, "", "DEBIT", false)
+	assert.NotNil(t, r)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "credit", TransactionsQuery{Type: "Credit"}.values().Get("type"))
+
+	q := TransactionsQuery{Type: TransactionTypeCredit, Limit: 50}.values()
+	assert.Equal(t, "credit", q.Get("type"))
+	assert.Equal(t, "50", q.Get("limit"))
+	assert.Equal(t, "true", q.Get("paginate"))
+}
+
+func TestGomono_TransactionsIterator(t *testing.T) {
+	it := client.TransactionsIterator(testPagedId, time.Time{}, time.Time{}, "", "", 0)
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Transaction().ID)
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, []string{"tnx-1-1", "tnx-1-2", "tnx-2-1", "tnx-2-2", "tnx-3-1", "tnx-3-2"}, ids)
+
+	it = client.TransactionsIterator("", time.Time{}, time.Time{}, "", "", 0)
+	assert.False(t, it.Next())
+	assert.NotNil(t, it.Err())
+}
+
+func TestNewTransactionsIterator(t *testing.T) {
+	stop := errors.New("stop")
+	it := NewTransactionsIterator([]Transaction{{ID: "tnx-1"}, {ID: "tnx-2"}}, stop)
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Transaction().ID)
+	}
+	assert.Equal(t, []string{"tnx-1", "tnx-2"}, ids)
+	assert.Equal(t, stop, it.Err())
+
+	var zero TransactionsIterator
+	assert.False(t, zero.Next())
+	assert.Nil(t, zero.Err())
+
+	var nilIt *TransactionsIterator
+	assert.False(t, nilIt.Next())
+	assert.Nil(t, nilIt.Err())
+}
+
+func TestGomono_AllTransactions(t *testing.T) {
+	tnxs, err := client.AllTransactions(testPagedId, time.Time{}, time.Time{}, "", "", 0)
 	assert.Nil(t, err)
+	assert.Equal(t, 6, len(tnxs))
+	assert.Equal(t, NewMoney(20000, ""), tnxs[5].Amount)
+
+	tnxs, err = client.AllTransactions(testPagedId, time.Time{}, time.Time{}, "", "", 2)
+	assert.Equal(t, ErrPageLimitReached, err)
+	assert.Equal(t, 4, len(tnxs))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tnxs, err = client.AllTransactionsContext(ctx, testPagedId, time.Time{}, time.Time{}, "", "", 0)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Empty(t, tnxs)
 }
 
 func TestGomono_TransactionByType(t *testing.T) {
 	r, err := client.CreditTransactions(testAccountId)
 	assert.NotNil(t, r)
-	assert.Equal(t, float64(2000000), r.Total)
+	assert.Equal(t, int64(2000000), r.Total.Minor)
+	assert.Equal(t, float64(2000000), r.Total.Float64())
 	assert.Equal(t, 2, len(r.History))
+	assert.Equal(t, PeriodTotal{Amount: NewMoney(1000000, ""), Period: "01-20"}, r.History[0])
 	assert.Nil(t, err)
 
 	r, err = client.DebitTransactions(testAccountId)
 	assert.NotNil(t, r)
-	assert.Equal(t, float64(1000000), r.Total)
+	assert.Equal(t, int64(1000000), r.Total.Minor)
 	assert.Equal(t, 2, len(r.History))
 	assert.Nil(t, err)
 }
@@ -124,7 +344,7 @@ func TestGomono_Income(t *testing.T) {
 	r, err := client.Income(testAccountId)
 	assert.NotNil(t, r)
 	assert.Equal(t, "INCOME", r.Type)
-	assert.Equal(t, float64(59700000), r.Amount)
+	assert.Equal(t, int64(59700000), r.Amount.Minor)
 	assert.Nil(t, err)
 }
 
@@ -134,7 +354,7 @@ func TestGomono_Identity(t *testing.T) {
 	assert.Equal(t, "ABDULHAMID", r.FirstName)
 	assert.Equal(t, "HASSAN", r.LastName)
 	assert.Equal(t, "NO", r.WatchListed)
-	assert.Equal(t, "06-May-1996", r.DateOfBirth)
+	assert.Equal(t, time.Date(1996, time.May, 6, 0, 0, 0, 0, time.UTC), r.DateOfBirth.Time)
 	assert.Nil(t, err)
 }
 
@@ -152,15 +372,843 @@ func TestGomono_LookupBVN(t *testing.T) {
 	assert.Equal(t, "ABDULHAMID", r.FirstName)
 	assert.Equal(t, "HASSAN", r.LastName)
 	assert.Equal(t, "NO", r.WatchListed)
-	assert.Equal(t, "06-May-1996", r.DateOfBirth)
+	assert.Equal(t, time.Date(1996, time.May, 6, 0, 0, 0, 0, time.UTC), r.DateOfBirth.Time)
+	assert.Nil(t, err)
+}
+
+func TestGomono_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r, err := client.InformationContext(ctx, testAccountId)
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, errors.As(err, &Error{}))
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tr, err := client.TransactionsContext(ctx, testAccountId, time.Time{}, time.Time{}, "", "", false)
+	assert.Nil(t, tr)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.False(t, errors.As(err, &Error{}))
+
+	r, err = client.InformationContext(context.Background(), testAccountId)
+	assert.NotNil(t, r)
 	assert.Nil(t, err)
 }
 
+func TestMoney(t *testing.T) {
+	var m Money
+	assert.Nil(t, json.Unmarshal([]byte(`37836709`), &m))
+	assert.Equal(t, int64(37836709), m.Minor)
+	assert.Equal(t, "₦378,367.09", m.String())
+	assert.Equal(t, 378367.09, m.Major())
+
+	assert.Nil(t, json.Unmarshal([]byte(`"375.6"`), &m))
+	assert.Equal(t, int64(376), m.Minor)
+	assert.NotNil(t, json.Unmarshal([]byte(`"ten"`), &m))
+
+	b, _ := json.Marshal(Transaction{Amount: NewMoney(5000, "")})
+	assert.Contains(t, string(b), `"amount":5000`)
+
+	sum, err := NewMoney(10517116, "NGN").Add(NewMoney(375, ""))
+	assert.Nil(t, err)
+	assert.Equal(t, NewMoney(10517491, "NGN"), sum)
+
+	diff, err := sum.Sub(NewMoney(10517500, "NGN"))
+	assert.Nil(t, err)
+	assert.Equal(t, "-₦0.09", diff.String())
+	assert.True(t, diff.IsNegative())
+	assert.Equal(t, int64(9), diff.Abs().Minor)
+
+	_, err = sum.Add(NewMoney(100, "GHS"))
+	assert.True(t, errors.Is(err, ErrCurrencyMismatch))
+
+	c, _ := NewMoney(100, "").Cmp(NewMoney(100, "NGN"))
+	assert.Equal(t, 0, c)
+	assert.Equal(t, "GHS 1,000,000.00", NewMoney(100000000, "GHS").String())
+	assert.Equal(t, NewMoney(1500, "NGN"), NewMoney(500, "NGN").Mul(3))
+}
+
+func TestDate(t *testing.T) {
+	var e StatementEntry
+	assert.Nil(t, json.Unmarshal([]byte(`{"date": "2020-12-01T00:00:00.000Z"}`), &e))
+	assert.Equal(t, time.Date(2020, time.December, 1, 0, 0, 0, 0, time.UTC), e.Date.Time)
+
+	for _, v := range []string{"01-12-2020", "01-Dec-2020", "2020-12-01"} {
+		d, err := ParseDate(v)
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2020, time.December, 1, 0, 0, 0, 0, time.UTC), d.Time)
+	}
+
+	_, err := ParseDate("December 1st")
+	assert.NotNil(t, err)
+
+	var i IdentityResponse
+	assert.Nil(t, json.Unmarshal([]byte(`{"dateOfBirth": "", "registrationDate": null}`), &i))
+	assert.True(t, i.DateOfBirth.IsZero())
+	assert.True(t, i.RegistrationDate.IsZero())
+
+	b, _ := json.Marshal(e)
+	assert.Contains(t, string(b), `"date":"2020-12-01T00:00:00Z"`)
+}
+
+func TestGomono_Sync(t *testing.T) {
+	r, err := client.Sync("")
+	assert.Nil(t, r)
+	assert.NotNil(t, err)
+
+	r, err = client.Sync(testAccountId)
+	assert.NotNil(t, r)
+	assert.Equal(t, SyncStatusSuccessful, r.Status)
+	assert.True(t, r.HasNewData)
+	assert.False(t, r.ReauthorisationRequired())
+	assert.Nil(t, err)
+
+	r, err = client.Sync(testReauthId)
+	assert.NotNil(t, r)
+	assert.Equal(t, SyncStatusFailed, r.Status)
+	assert.True(t, r.ReauthorisationRequired())
+	assert.Equal(t, testReauthToken, r.Token)
+	assert.Nil(t, err)
+
+	r, err = client.Sync(testMissingId)
+	assert.Nil(t, r)
+	assert.NotNil(t, err)
+}
+
+func TestGomono_Reauthorise(t *testing.T) {
+	r, err := client.Reauthorise("")
+	assert.Nil(t, r)
+	assert.NotNil(t, err)
+
+	r, err = client.Reauthorise(testReauthId)
+	assert.NotNil(t, r)
+	assert.Equal(t, testReauthToken, r.Token)
+	assert.Nil(t, err)
+}
+
+func TestGomono_Unlink(t *testing.T) {
+	r, err := client.Unlink("")
+	assert.Nil(t, r)
+	assert.NotNil(t, err)
+
+	r, err = client.Unlink(testAccountId)
+	assert.NotNil(t, r)
+	assert.Equal(t, "Account unlinked successfully", r.Message)
+	assert.Nil(t, err)
+
+	r, err = client.Unlink(testUnlinkedId)
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, ErrAccountUnlinked))
+	assert.False(t, errors.Is(err, ErrNotFound))
+
+	assert.True(t, errors.Is(Error{Code: 400, Type: "ACCOUNT_UNLINKED"}, ErrAccountUnlinked))
+	assert.False(t, errors.Is(Error{Code: 400, Message: "Account cannot be unlinked"}, ErrAccountUnlinked))
+	assert.False(t, errors.Is(Error{Code: 500, Message: "This account has already been unlinked"}, ErrAccountUnlinked))
+}
+
+func TestGomono_Errors(t *testing.T) {
+	r, err := client.Information(testMissingId)
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.False(t, errors.Is(err, ErrReauthRequired))
+
+	var e Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, 404, e.Code)
+	assert.Equal(t, "Account not found", e.Message)
+	assert.Equal(t, "NOT_FOUND", e.Type)
+	assert.Equal(t, "404", e.ErrorCode)
+	assert.Contains(t, e.Body, "Account not found")
+
+	r, err = client.Information(testReauthId)
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, ErrReauthRequired))
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, "REAUTHORISATION_REQUIRED", e.ErrorCode)
+
+	r, err = client.Information("unknown")
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, ErrServer))
+
+	g, _ := New(Config{SecretKey: "WRONG_KEY", HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: mockServer.URL})
+	r, err = g.Information(testAccountId)
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, "Invalid secret key", e.Message)
+
+	assert.True(t, errors.Is(Error{Code: 429}, ErrRateLimited))
+}
+
+type testTransport func(req *http.Request) (*http.Response, error)
+
+func (f testTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestGomono_ErrorsFromCustomTransport(t *testing.T) {
+	//fake transports often leave Response.Request unset
+	transport := testTransport(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 404,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Account not found"}`)),
+		}, nil
+	})
+
+	g, _ := New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{Transport: transport}, ApiUrl: "https://api.withmono.com"})
+	r, err := g.Information(testAccountId)
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	var e Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, "https://api.withmono.com/accounts/"+testAccountId, e.Endpoint)
+	assert.Equal(t, "Account not found", e.Message)
+
+	pdf := &StatementResponsePdf{Status: StatementStatusBuilt, Path: "https://api.withmono.com/statements/missing.pdf"}
+	_, err = g.DownloadStatement(context.Background(), pdf, ioutil.Discard)
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestGomono_DownloadStatementKey(t *testing.T) {
+	keys := map[string]string{}
+	transport := testTransport(func(req *http.Request) (*http.Response, error) {
+		keys[req.URL.String()] = req.Header.Get("mono-sec-key")
+		return &http.Response{
+			StatusCode:    200,
+			Header:        http.Header{"Content-Type": {"application/pdf"}},
+			ContentLength: -1,
+			Body:          ioutil.NopCloser(strings.NewReader(testPdfBody)),
+		}, nil
+	})
+
+	g, _ := New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{Transport: transport}, ApiUrl: "https://api.withmono.com"})
+	paths := []string{
+		"https://api.withmono.com/statements/x.pdf",
+		"http://api.withmono.com/statements/x.pdf",
+		"https://statements.example.com/x.pdf",
+	}
+	for _, path := range paths {
+		_, err := g.DownloadStatement(context.Background(), &StatementResponsePdf{Status: StatementStatusBuilt, Path: path}, ioutil.Discard)
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, map[string]string{
+		paths[0]: testSecretKey,
+		paths[1]: "",
+		paths[2]: "",
+	}, keys)
+}
+
+func TestGomono_Middleware(t *testing.T) {
+	var seen []string
+	var headers []string
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				ep, _ := EndpointFromContext(req.Context())
+				seen = append(seen, name+":"+ep.Name+":"+ep.Template)
+				req.Header.Set("X-Correlation-ID", "corr-123")
+				resp, err := next(req)
+				if resp != nil {
+					headers = append(headers, resp.Header.Get("Content-type"))
+				}
+				return resp, err
+			}
+		}
+	}
+
+	g, _ := New(Config{
+		SecretKey:  testSecretKey,
+		HttpClient: &http.Client{Timeout: time.Second},
+		ApiUrl:     mockServer.URL,
+		Middleware: []Middleware{trace("outer"), trace("inner")},
+	})
+
+	r, err := g.Income(testAccountId)
+	assert.NotNil(t, r)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"outer:Income:/accounts/{id}/income", "inner:Income:/accounts/{id}/income"}, seen)
+	assert.Equal(t, []string{"application/json", "application/json"}, headers)
+
+	block := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("blocked")
+		}
+	}
+	g, _ = New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{}, ApiUrl: mockServer.URL, Middleware: []Middleware{block}})
+	r, err = g.Income(testAccountId)
+	assert.Nil(t, r)
+	assert.Contains(t, err.Error(), "blocked")
+
+	//a response built by middleware carries no Request
+	unavailable := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 503,
+				Header:     http.Header{"Retry-After": []string{"5"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"message": "Maintenance"}`)),
+			}, nil
+		}
+	}
+	g, _ = New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{}, ApiUrl: mockServer.URL, Middleware: []Middleware{unavailable}})
+	r, err = g.Income(testAccountId)
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, ErrServer))
+
+	var e Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, "Maintenance", e.Message)
+	assert.Equal(t, 5*time.Second, e.RetryAfter)
+	assert.Equal(t, mockServer.URL+"/accounts/"+testAccountId+"/income", e.Endpoint)
+}
+
+type testLogger struct {
+	entries []string
+}
+
+func (l *testLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.entries = append(l.entries, fmt.Sprint(append([]interface{}{msg}, keysAndValues...)...))
+}
+
+func (l *testLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.Info(msg, keysAndValues...)
+}
+
+func TestGomono_Logger(t *testing.T) {
+	logger := &testLogger{}
+	g, _ := New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: mockServer.URL, Logger: logger})
+
+	r, err := g.Identity(testAccountId)
+	assert.NotNil(t, r)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(logger.entries))
+	assert.Contains(t, logger.entries[0], "gomono: request completed")
+	assert.Contains(t, logger.entries[0], "Identity")
+	assert.Contains(t, logger.entries[0], "200")
+
+	g, _ = New(Config{SecretKey: "WRONG_KEY", HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: mockServer.URL, Logger: logger})
+	r, err = g.LookupBVN("12345678974")
+	assert.Nil(t, r)
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	assert.Equal(t, 2, len(logger.entries))
+	assert.Contains(t, logger.entries[1], "gomono: request failed")
+	assert.Contains(t, logger.entries[1], "Invalid secret key")
+	assert.Contains(t, logger.entries[1], `{"bvn":"[REDACTED]"}`)
+	assert.NotContains(t, logger.entries[1], "12345678974")
+}
+
+func TestRedact(t *testing.T) {
+	body := `{"bvn": "6800", "nin": "000000", "phoneNumber1": "08012345678", "email": "tomiwa.jr@gmail.com", "accountNumber": "0788164862", "firstName": "ABDULHAMID"}`
+	redacted := Redact(body)
+	for _, v := range []string{"6800", "000000", "08012345678", "tomiwa.jr@gmail.com", "0788164862"} {
+		assert.NotContains(t, redacted, v)
+	}
+	assert.Contains(t, redacted, "ABDULHAMID")
+
+	assert.Equal(t, "call [REDACTED] or mail [REDACTED] about [REDACTED]'s account", Redact("call +2348012345678 or mail jane@example.com about 12345678901's account"))
+	assert.Equal(t, "https://api.withmono.com/lookup?bvn=[REDACTED]&page=2", Redact("https://api.withmono.com/lookup?bvn=22212345678&page=2"))
+	assert.Equal(t, "/accounts/5fc68b964bdcbe4eb164e852/transactions", Redact("/accounts/5fc68b964bdcbe4eb164e852/transactions"))
+}
+
+type (
+	testTracer struct {
+		spans []*testSpan
+	}
+
+	testSpan struct {
+		name       string
+		attributes map[string]interface{}
+		errs       []error
+		ended      bool
+	}
+
+	testSpanKey struct{}
+)
+
+func (tr *testTracer) Start(ctx context.Context, spanName string) (context.Context, Span) {
+	span := &testSpan{name: spanName, attributes: make(map[string]interface{})}
+	tr.spans = append(tr.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) { s.attributes[key] = value }
+func (s *testSpan) RecordError(err error)                      { s.errs = append(s.errs, err) }
+func (s *testSpan) End()                                       { s.ended = true }
+
+func TestGomono_Tracer(t *testing.T) {
+	tracer := &testTracer{}
+	var propagated bool
+	spanCheck := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			_, propagated = req.Context().Value(testSpanKey{}).(*testSpan)
+			return next(req)
+		}
+	}
+
+	g, _ := New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: mockServer.URL, Tracer: tracer, Middleware: []Middleware{spanCheck}})
+
+	r, err := g.IdentityContext(context.Background(), testAccountId)
+	assert.NotNil(t, r)
+	assert.Nil(t, err)
+	assert.True(t, propagated)
+	assert.Equal(t, 1, len(tracer.spans))
+
+	span := tracer.spans[0]
+	assert.Equal(t, "gomono.Identity", span.name)
+	assert.True(t, span.ended)
+	assert.Equal(t, "/accounts/{id}/identity", span.attributes[AttributeEndpoint])
+	assert.Equal(t, "GET", span.attributes[AttributeHTTPMethod])
+	assert.Equal(t, 200, span.attributes[AttributeHTTPStatus])
+	assert.Equal(t, 0, span.attributes[AttributeRetryCount])
+	assert.Equal(t, hashAccountID(testAccountId), span.attributes[AttributeAccountIDHash])
+	assert.NotContains(t, fmt.Sprint(span.attributes), testAccountId)
+	assert.Empty(t, span.errs)
+
+	_, err = g.Information(testMissingId)
+	assert.NotNil(t, err)
+	span = tracer.spans[1]
+	assert.Equal(t, 404, span.attributes[AttributeHTTPStatus])
+	assert.Equal(t, []error{err}, span.errs)
+
+	_, err = g.Institutions()
+	assert.Nil(t, err)
+	_, ok := tracer.spans[2].attributes[AttributeAccountIDHash]
+	assert.False(t, ok)
+}
+
+func TestGomono_Metrics(t *testing.T) {
+	metrics := NewPrometheusCollector(0.5, 0.1)
+	g, _ := New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: mockServer.URL, Metrics: metrics})
+
+	_, err := g.Income(testAccountId)
+	assert.Nil(t, err)
+	_, err = g.Information(testMissingId)
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = g.InformationContext(ctx, testAccountId)
+	assert.NotNil(t, err)
+
+	var buf bytes.Buffer
+	_, err = metrics.WriteTo(&buf)
+	assert.Nil(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, `gomono_requests_total{endpoint="/accounts/{id}/income",method="GET",status="200"} 1`)
+	assert.Contains(t, out, `gomono_requests_total{endpoint="/accounts/{id}",method="GET",status="404"} 1`)
+	assert.Contains(t, out, `gomono_request_errors_total{endpoint="/accounts/{id}",kind="mono"} 1`)
+	assert.Contains(t, out, `gomono_request_errors_total{endpoint="/accounts/{id}",kind="canceled"} 1`)
+	assert.Contains(t, out, `gomono_response_bytes_total{endpoint="/accounts/{id}/income"} 93`)
+	assert.Contains(t, out, `gomono_request_duration_seconds_bucket{endpoint="/accounts/{id}/income",le="0.1"} 1`)
+	assert.Contains(t, out, `gomono_request_duration_seconds_count{endpoint="/accounts/{id}"} 2`)
+	assert.NotContains(t, out, testAccountId)
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, rec.Body.String(), "# TYPE gomono_request_duration_seconds histogram")
+}
+
+func TestGomono_RateLimit(t *testing.T) {
+	g, err := New(Config{
+		SecretKey:  testSecretKey,
+		HttpClient: &http.Client{Timeout: time.Second},
+		ApiUrl:     mockServer.URL,
+		RateLimit: &RateLimit{
+			PerEndpoint: map[string]Limit{"Income": {Rate: 50, Burst: 1}},
+		},
+	})
+	assert.Nil(t, err)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		_, err = g.Income(testAccountId)
+		assert.Nil(t, err)
+	}
+	assert.True(t, time.Since(start) >= 70*time.Millisecond)
+
+	start = time.Now()
+	for i := 0; i < 5; i++ {
+		_, err = g.Identity(testAccountId)
+		assert.Nil(t, err)
+	}
+	assert.True(t, time.Since(start) < 70*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	g.IncomeContext(ctx, testAccountId)
+	_, err = g.IncomeContext(ctx, testAccountId)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	_, err = New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{}, ApiUrl: mockServer.URL, RateLimit: &RateLimit{Global: Limit{Rate: -1}}})
+	assert.NotNil(t, err)
+
+	_, err = New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{}, ApiUrl: mockServer.URL, RateLimit: &RateLimit{PerEndpoint: map[string]Limit{"Transaction": {Rate: 1}}}})
+	assert.NotNil(t, err)
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	l := newRateLimiter(&RateLimit{
+		Global:      Limit{Rate: 0.001, Burst: 2},
+		PerEndpoint: map[string]Limit{"Income": {Rate: 0.001, Burst: 1}},
+	})
+
+	income := withEndpoint(context.Background(), endpointIncome)
+	assert.Nil(t, l.wait(income))
+
+	//a call cancelled while waiting on its endpoint gives the global token back
+	ctx, cancel := context.WithTimeout(income, 5*time.Millisecond)
+	defer cancel()
+	assert.True(t, errors.Is(l.wait(ctx), context.DeadlineExceeded))
+
+	ctx, cancel = context.WithTimeout(withEndpoint(context.Background(), endpointIdentity), 5*time.Millisecond)
+	defer cancel()
+	assert.Nil(t, l.wait(ctx))
+}
+
+func TestRateLimiter_Adapt(t *testing.T) {
+	l := newRateLimiter(&RateLimit{})
+	assert.Equal(t, time.Duration(0), l.global.reserve())
+
+	resp := &http.Response{StatusCode: 429, Header: http.Header{"Retry-After": []string{"2"}}}
+	l.adapt(context.Background(), resp)
+	assert.True(t, l.global.reserve() > time.Second)
+
+	l = newRateLimiter(&RateLimit{})
+	resp = &http.Response{StatusCode: 200, Header: http.Header{"X-Ratelimit-Remaining": []string{"0"}, "X-Ratelimit-Reset": []string{"3"}}}
+	l.adapt(context.Background(), resp)
+	assert.True(t, l.global.reserve() > 2*time.Second)
+
+	l = newRateLimiter(&RateLimit{})
+	resp = &http.Response{StatusCode: 200, Header: http.Header{"X-Ratelimit-Remaining": []string{"10"}, "X-Ratelimit-Reset": []string{"3"}}}
+	l.adapt(context.Background(), resp)
+	assert.Equal(t, time.Duration(0), l.global.reserve())
+}
+
+func TestGomono_CircuitBreaker(t *testing.T) {
+	var healthy, hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(503)
+			return
+		}
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `{"account": {"_id": "%v"}}`, testAccountId)
+	}))
+	defer server.Close()
+
+	var changes []string
+	breaker := &CircuitBreaker{
+		Default: Thresholds{Failures: 2, OpenFor: 20 * time.Millisecond},
+		OnStateChange: func(endpoint string, from, to CircuitState) {
+			changes = append(changes, fmt.Sprintf("%v %v->%v", endpoint, from, to))
+		},
+	}
+	g, _ := New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: server.URL, CircuitBreaker: breaker})
+
+	for i := 0; i < 2; i++ {
+		_, err := g.Information(testAccountId)
+		assert.True(t, errors.Is(err, ErrServer))
+	}
+
+	_, err := g.Information(testAccountId)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+
+	//other endpoints have their own circuit
+	_, err = g.Identity(testAccountId)
+	assert.True(t, errors.Is(err, ErrServer))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+
+	//a failed probe opens the circuit again
+	time.Sleep(30 * time.Millisecond)
+	_, err = g.Information(testAccountId)
+	assert.True(t, errors.Is(err, ErrServer))
+	_, err = g.Information(testAccountId)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+
+	atomic.StoreInt32(&healthy, 1)
+	time.Sleep(30 * time.Millisecond)
+	r, err := g.Information(testAccountId)
+	assert.Nil(t, err)
+	assert.Equal(t, testAccountId, r.Account.ID)
+
+	assert.Equal(t, []string{
+		"Information closed->open",
+		"Information open->half-open",
+		"Information half-open->open",
+		"Information open->half-open",
+		"Information half-open->closed",
+	}, changes)
+
+	//a failing SecretKeyProvider does not count against the circuit
+	changes = nil
+	os.Setenv("GOMONO_TEST_BREAKER_KEY", testSecretKey)
+	g, _ = New(Config{HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: mockServer.URL, SecretKeyProvider: NewEnvKeyProvider("GOMONO_TEST_BREAKER_KEY"), CircuitBreaker: breaker})
+	os.Unsetenv("GOMONO_TEST_BREAKER_KEY")
+	pdf := &StatementResponsePdf{Status: StatementStatusBuilt, Path: mockServer.URL + "/statements/pvLhFR89Id2zrnPGJZcM.pdf"}
+	for i := 0; i < 3; i++ {
+		_, err = g.DownloadStatement(context.Background(), pdf, ioutil.Discard)
+		assert.NotNil(t, err)
+		assert.False(t, errors.Is(err, ErrCircuitOpen))
+	}
+	assert.Empty(t, changes)
+
+	_, err = New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{}, ApiUrl: server.URL, CircuitBreaker: &CircuitBreaker{Default: Thresholds{Failures: -1}}})
+	assert.NotNil(t, err)
+
+	_, err = New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{}, ApiUrl: server.URL, CircuitBreaker: &CircuitBreaker{PerEndpoint: map[string]Thresholds{"Transaction": {Failures: 3}}}})
+	assert.NotNil(t, err)
+}
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry(Config{HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: mockServer.URL, RateLimit: &RateLimit{}})
+	assert.Nil(t, reg.Register("payments", testSecretKey))
+	assert.Nil(t, reg.Register("lending", "WRONG_KEY"))
+	assert.NotNil(t, reg.Register("", testSecretKey))
+	assert.NotNil(t, reg.Register("empty", ""))
+	assert.Equal(t, []string{"lending", "payments"}, reg.Tenants())
+
+	payments, err := reg.FromContext(WithTenant(context.Background(), "payments"))
+	assert.Nil(t, err)
+	_, err = payments.Information(testAccountId)
+	assert.Nil(t, err)
+
+	lending, err := reg.Client("lending")
+	assert.Nil(t, err)
+	_, err = lending.Information(testAccountId)
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	assert.Equal(t, payments.(*gomono).client, lending.(*gomono).client)
+
+	//a client held across a rotation keeps working with its key
+	assert.Nil(t, reg.Register("lending", testSecretKey))
+	rotated, _ := reg.Client("lending")
+	_, err = rotated.Information(testAccountId)
+	assert.Nil(t, err)
+	_, err = lending.Information(testAccountId)
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	assert.Equal(t, lending.(*gomono).limiter, rotated.(*gomono).limiter)
+
+	reg.Remove("lending")
+	_, err = reg.Client("lending")
+	assert.True(t, errors.Is(err, ErrUnknownTenant))
+
+	_, err = reg.FromContext(context.Background())
+	assert.True(t, errors.Is(err, ErrUnknownTenant))
+}
+
+func TestGomono_SecretKeyProvider(t *testing.T) {
+	keys := []string{"OLD_KEY", testSecretKey}
+	fetches := 0
+	provider := NewCachedKeyProvider(0, func(ctx context.Context) (string, error) {
+		key := keys[fetches]
+		fetches++
+		return key, nil
+	})
+
+	tracer := &testTracer{}
+	g, err := New(Config{HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: mockServer.URL, SecretKeyProvider: provider, Tracer: tracer})
+	assert.Nil(t, err)
+
+	//the rejected key is refreshed and the call made again
+	r, err := g.Information(testAccountId)
+	assert.Nil(t, err)
+	assert.Equal(t, testAccountId, r.Account.ID)
+	assert.Equal(t, 2, fetches)
+	assert.Equal(t, 1, tracer.spans[0].attributes[AttributeRetryCount])
+
+	_, err = g.Income(testAccountId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, fetches)
+
+	os.Setenv("GOMONO_TEST_SECRET_KEY", testSecretKey)
+	defer os.Unsetenv("GOMONO_TEST_SECRET_KEY")
+	g, _ = New(Config{HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: mockServer.URL, SecretKeyProvider: NewEnvKeyProvider("GOMONO_TEST_SECRET_KEY")})
+	_, err = g.Information(testAccountId)
+	assert.Nil(t, err)
+
+	g, err = New(Config{HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: mockServer.URL, SecretKeyProvider: NewEnvKeyProvider("GOMONO_TEST_MISSING_KEY")})
+	assert.Nil(t, g)
+	assert.NotNil(t, err)
+
+	dir, err := ioutil.TempDir("", "gomono")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "secret-key")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("WRONG\n"), 0600))
+
+	fileProvider := NewFileKeyProvider(path)
+	g, _ = New(Config{HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: mockServer.URL, SecretKeyProvider: fileProvider})
+	_, err = g.Information(testAccountId)
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte(testSecretKey+"\n"), 0600))
+	key, err := fileProvider.SecretKey(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, testSecretKey, key)
+	_, err = g.Information(testAccountId)
+	assert.Nil(t, err)
+
+	//the environment is detected from the provider and keys are checked against it on every call
+	live := NewCachedKeyProvider(time.Minute, func(ctx context.Context) (string, error) { return "live_sk_abc", nil })
+	_, err = New(Config{HttpClient: &http.Client{}, ApiUrl: mockServer.URL, SecretKeyProvider: live, Environment: EnvironmentSandbox})
+	assert.True(t, errors.Is(err, ErrEnvironmentMismatch))
+
+	os.Setenv("GOMONO_TEST_SECRET_KEY", "test_sk_abc")
+	g, err = New(Config{HttpClient: &http.Client{}, ApiUrl: mockServer.URL, SecretKeyProvider: NewEnvKeyProvider("GOMONO_TEST_SECRET_KEY")})
+	assert.Nil(t, err)
+	assert.Equal(t, EnvironmentSandbox, g.Environment())
+
+	os.Setenv("GOMONO_TEST_SECRET_KEY", "live_sk_abc")
+	_, err = g.Information(testAccountId)
+	assert.True(t, errors.Is(err, ErrEnvironmentMismatch))
+
+	_, err = New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{}, ApiUrl: mockServer.URL, SecretKeyProvider: provider})
+	assert.NotNil(t, err)
+}
+
+func TestGomono_Retry(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1)%3 != 0 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(503)
+			return
+		}
+		w.WriteHeader(200)
+		if r.URL.Path == "/account/auth" {
+			fmt.Fprintf(w, `{"id": "%v"}`, testAccountId)
+			return
+		}
+		fmt.Fprintf(w, `{"account": {"_id": "%v"}}`, testAccountId)
+	}))
+	defer server.Close()
+
+	policy := RetryPolicy{
+		MaxAttempts:          3,
+		BaseDelay:            time.Millisecond,
+		MaxDelay:             10 * time.Millisecond,
+		Jitter:               0.5,
+		RetryableStatusCodes: []int{503},
+	}
+	g, _ := New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: server.URL, Retry: policy})
+
+	r, err := g.Information(testAccountId)
+	assert.NotNil(t, r)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+
+	//POST calls are not retried unless opted in
+	id, err := g.ExchangeToken(testMonoConnectCode)
+	assert.Empty(t, id)
+	assert.Equal(t, 503, err.(Error).Code)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&hits))
+
+	policy.RetryNonIdempotent = true
+	tracer := &testTracer{}
+	metrics := NewPrometheusCollector()
+	g, _ = New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: server.URL, Retry: policy, Tracer: tracer, Metrics: metrics})
+
+	id, err = g.ExchangeToken(testMonoConnectCode)
+	assert.Equal(t, testAccountId, id)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&hits))
+	assert.Equal(t, 1, tracer.spans[0].attributes[AttributeRetryCount])
+	assert.Equal(t, uint64(1), metrics.retries["/account/auth"])
+
+	_, err = New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{}, ApiUrl: server.URL, Retry: RetryPolicy{Jitter: 2}})
+	assert.NotNil(t, err)
+}
+
+func TestGomono_DownloadStatementRetry(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("mono-sec-key") != testSecretKey {
+			w.WriteHeader(401)
+			return
+		}
+		if atomic.AddInt32(&hits, 1)%3 != 0 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(503)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		io.WriteString(w, testPdfBody)
+	}))
+	defer server.Close()
+
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryableStatusCodes: []int{503}}
+	tracer := &testTracer{}
+	g, _ := New(Config{SecretKey: testSecretKey, HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: server.URL, Retry: policy, Tracer: tracer})
+
+	pdf := &StatementResponsePdf{Status: StatementStatusBuilt, Path: server.URL + "/statements/x.pdf"}
+	var buf bytes.Buffer
+	n, err := g.DownloadStatement(context.Background(), pdf, &buf)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(testPdfBody)), n)
+	assert.Equal(t, testPdfBody, buf.String())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+	assert.Equal(t, 2, tracer.spans[0].attributes[AttributeRetryCount])
+
+	//a rejected key is refreshed and the download made again
+	keys := []string{"OLD_KEY", testSecretKey}
+	fetches := 0
+	provider := NewCachedKeyProvider(0, func(ctx context.Context) (string, error) {
+		key := keys[fetches]
+		fetches++
+		return key, nil
+	})
+	g, _ = New(Config{HttpClient: &http.Client{Timeout: time.Second}, ApiUrl: server.URL, SecretKeyProvider: provider, Retry: policy})
+
+	buf.Reset()
+	_, err = g.DownloadStatement(context.Background(), pdf, &buf)
+	assert.Nil(t, err)
+	assert.Equal(t, testPdfBody, buf.String())
+	assert.Equal(t, 2, fetches)
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	d, ok := p.delay(1, nil)
+	assert.True(t, ok)
+	assert.Equal(t, 100*time.Millisecond, d)
+
+	d, _ = p.delay(3, nil)
+	assert.Equal(t, 400*time.Millisecond, d)
+
+	d, _ = p.delay(10, nil)
+	assert.Equal(t, time.Second, d)
+
+	d, ok = p.delay(1, Error{Code: 429, RetryAfter: 500 * time.Millisecond})
+	assert.True(t, ok)
+	assert.Equal(t, 500*time.Millisecond, d)
+
+	_, ok = p.delay(1, Error{Code: 429, RetryAfter: time.Minute})
+	assert.False(t, ok)
+
+	assert.Equal(t, 2*time.Second, parseRetryAfter("2"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+}
+
 //StartServer initializes a test HTTP server useful for request mocking, Integration tests and Client configuration
 func testServer() *httptest.Server {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-type", "application/json")
 
+		if r.Header.Get("mono-sec-key") != testSecretKey {
+			w.WriteHeader(401)
+			fmt.Fprintf(w, `{"message": "Invalid secret key"}`)
+			return
+		}
+
 		switch r.URL.Path {
 		case "/account/auth":
 			successBody := fmt.Sprintf(`{"id": "%v"}`, testAccountId)
@@ -218,12 +1266,40 @@ func testServer() *httptest.Server {
 			w.WriteHeader(200)
 			fmt.Fprintf(w, body)
 
+		case "/statements/pvLhFR89Id2zrnPGJZcM.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.WriteHeader(200)
+			fmt.Fprint(w, testPdfBody)
+
+		case "/statements/html.pdf":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(200)
+			fmt.Fprintf(w, "<html></html>")
+
+		case fmt.Sprintf("/accounts/%v/statement/jobs/%v", testAccountId, testBuildingJobId):
+			status := "BUILDING"
+			if atomic.AddInt32(&buildingJobPolls, 1) >= 3 {
+				status = "BUILT"
+			}
+			w.WriteHeader(200)
+			fmt.Fprintf(w, `{"id": "%v", "status": "%v", "path": "https://api.withmono.com/statements/pvLhFR89Id2zrnPGJZcM.pdf"}`, testBuildingJobId, status)
+
+		case fmt.Sprintf("/accounts/%v/statement/jobs/%v", testAccountId, testFailedJobId):
+			w.WriteHeader(200)
+			fmt.Fprintf(w, `{"id": "%v", "status": "FAILED"}`, testFailedJobId)
+
 		case fmt.Sprintf("/accounts/%v/statement/jobs/%v", testAccountId, testJobId):
 			body := fmt.Sprintf(`{"id": "%v", "status": "COMPLETE", "path": "https://api.withmono.com/statements/pvLhFR89Id2zrnPGJZcM.pdf"}`, testJobId)
 			w.WriteHeader(200)
 			fmt.Fprintf(w, body)
 
 		case fmt.Sprintf("/accounts/%v/transactions", testAccountId):
+			if q := r.URL.Query(); q.Get("start") != "01-10-2020" || q.Get("end") != "07-10-2020" {
+				w.WriteHeader(400)
+				fmt.Fprintf(w, `{"message": "Invalid date range"}`)
+				return
+			}
+
 			body := `{
   "paging": {
     "total": 190,
@@ -435,6 +1511,51 @@ func testServer() *httptest.Server {
 			w.WriteHeader(200)
 			fmt.Fprintf(w, body)
 
+		case fmt.Sprintf("/accounts/%v/transactions", testPagedId):
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			if page == 0 {
+				page = 1
+			}
+
+			next := ""
+			if page < 3 {
+				next = fmt.Sprintf("https://api.withmono.com/accounts/%v/transactions?page=%v", testPagedId, page+1)
+			}
+
+			w.WriteHeader(200)
+			fmt.Fprintf(w, `{"paging": {"total": 5, "page": %v, "next": "%v"}, "data": [
+				{"_id": "tnx-%v-1", "amount": 10000, "type": "debit"},
+				{"_id": "tnx-%v-2", "amount": 20000, "type": "credit"}
+			]}`, page, next, page, page)
+
+		case fmt.Sprintf("/accounts/%v/sync", testAccountId):
+			w.WriteHeader(200)
+			fmt.Fprintf(w, `{"status": "successful", "hasNewData": true, "code": "SYNC_SUCCESSFUL"}`)
+
+		case fmt.Sprintf("/accounts/%v/sync", testReauthId):
+			w.WriteHeader(400)
+			fmt.Fprintf(w, `{"status": "failed", "message": "Reauthorisation required", "code": "REAUTHORISATION_REQUIRED", "token": "%v"}`, testReauthToken)
+
+		case fmt.Sprintf("/accounts/%v/reauthorise", testReauthId):
+			w.WriteHeader(200)
+			fmt.Fprintf(w, `{"token": "%v"}`, testReauthToken)
+
+		case fmt.Sprintf("/accounts/%v/unlink", testAccountId):
+			w.WriteHeader(200)
+			fmt.Fprintf(w, `{"message": "Account unlinked successfully"}`)
+
+		case fmt.Sprintf("/accounts/%v/unlink", testUnlinkedId):
+			w.WriteHeader(400)
+			fmt.Fprintf(w, `{"message": "This account has already been unlinked"}`)
+
+		case fmt.Sprintf("/accounts/%v", testMissingId):
+			w.WriteHeader(404)
+			fmt.Fprintf(w, `{"message": "Account not found", "type": "NOT_FOUND", "code": 404}`)
+
+		case fmt.Sprintf("/accounts/%v", testReauthId):
+			w.WriteHeader(400)
+			fmt.Fprintf(w, `{"message": "Reauthorisation required", "type": "ACCOUNT_ERROR", "code": "REAUTHORISATION_REQUIRED"}`)
+
 		default:
 			w.WriteHeader(500)
 		}
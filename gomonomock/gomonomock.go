@@ -0,0 +1,110 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+//Package gomonomock provides Mock, an implementation of gomono.Gomono for unit tests.
+//
+//Every method is stubbed through a function field named after it and every call is recorded:
+//
+//	m := &gomonomock.Mock{}
+//	m.InformationFunc = func(id string) (*gomono.InformationResponse, error) {
+//		return &gomono.InformationResponse{}, nil
+//	}
+//
+//	service := NewService(m)
+//	...
+//	m.AssertCalled(t, "Information", 1)
+//
+//Methods without a stub record the call and fail with ErrNotStubbed. Iterators are stubbed with
+//gomono.NewTransactionsIterator and, when left unstubbed, report ErrNotStubbed from Err.
+//Mock is generated from the gomono.Gomono interface, run go generate after changing it.
+package gomonomock
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+//go:generate go run ./internal/gen
+
+//ErrNotStubbed is returned by the methods of Mock that have no stub
+var ErrNotStubbed = errors.New("gomonomock: method not stubbed")
+
+type (
+	//Call is a call made to a Mock. Args hold the arguments in order, the context included.
+	Call struct {
+		Method string
+		Args   []interface{}
+	}
+
+	//TestingT is the part of *testing.T used by the assertions
+	TestingT interface {
+		Helper()
+		Errorf(format string, args ...interface{})
+	}
+
+	recorder struct {
+		mu    sync.Mutex
+		calls []Call
+	}
+)
+
+func (r *recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+//Calls returns every call made so far, in order
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call{}, r.calls...)
+}
+
+//CallsTo returns the calls made to a method, in order
+func (r *recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+//CallCount returns how many times a method was called
+func (r *recorder) CallCount(method string) int {
+	return len(r.CallsTo(method))
+}
+
+//AssertCalled fails the test unless the method was called exactly times times
+func (r *recorder) AssertCalled(t TestingT, method string, times int) bool {
+	t.Helper()
+	if n := r.CallCount(method); n != times {
+		t.Errorf("gomonomock: expected %v to be called %v time(s), it was called %v time(s)", method, times, n)
+		return false
+	}
+	return true
+}
+
+//AssertNotCalled fails the test if the method was called
+func (r *recorder) AssertNotCalled(t TestingT, method string) bool {
+	t.Helper()
+	return r.AssertCalled(t, method, 0)
+}
+
+//Reset forgets the calls made so far. Stubs are kept.
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+func notStubbed(method string) error {
+	return fmt.Errorf("%w: %v", ErrNotStubbed, method)
+}
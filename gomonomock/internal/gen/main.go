@@ -0,0 +1,198 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+//Command gen writes gomonomock/mock.go from the methods of the gomono.Gomono interface.
+//It is run by go generate from the gomonomock directory.
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/jcobhams/gomono"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const header = `// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+
+// Code generated by go run ./internal/gen; DO NOT EDIT.
+
+package gomonomock
+`
+
+var (
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	contextType  = reflect.TypeOf((*context.Context)(nil)).Elem()
+	iteratorType = reflect.TypeOf((*gomono.TransactionsIterator)(nil))
+)
+
+func main() {
+	src, err := generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile("mock.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+//generate returns the formatted source of mock.go
+func generate() ([]byte, error) {
+	iface := reflect.TypeOf((*gomono.Gomono)(nil)).Elem()
+
+	imports := map[string]bool{}
+	var fields, methods bytes.Buffer
+
+	for i := 0; i < iface.NumMethod(); i++ {
+		m := iface.Method(i)
+		collectImports(m.Type, imports)
+
+		params, args := signatureParams(m.Type)
+		results := signatureResults(m.Type)
+
+		fmt.Fprintf(&fields, "\t%vFunc func(%v) %v\n", m.Name, params, results)
+
+		fmt.Fprintf(&methods, "\n// %v records the call and runs %vFunc\n", m.Name, m.Name)
+		fmt.Fprintf(&methods, "func (m *Mock) %v(%v) %v {\n", m.Name, params, results)
+		if len(args) > 0 {
+			fmt.Fprintf(&methods, "\tm.record(%q, %v)\n", m.Name, strings.Join(args, ", "))
+		} else {
+			fmt.Fprintf(&methods, "\tm.record(%q)\n", m.Name)
+		}
+		fmt.Fprintf(&methods, "\tif m.%vFunc == nil {\n", m.Name)
+		if m.Type.NumOut() > 0 {
+			fmt.Fprintf(&methods, "\t\treturn %v\n", notStubbedResults(m.Type, m.Name))
+		} else {
+			fmt.Fprintf(&methods, "\t\treturn\n")
+		}
+		fmt.Fprintf(&methods, "\t}\n")
+		if m.Type.NumOut() > 0 {
+			fmt.Fprintf(&methods, "\treturn m.%vFunc(%v)\n", m.Name, strings.Join(args, ", "))
+		} else {
+			fmt.Fprintf(&methods, "\tm.%vFunc(%v)\n", m.Name, strings.Join(args, ", "))
+		}
+		fmt.Fprintf(&methods, "}\n")
+	}
+
+	var b bytes.Buffer
+	b.WriteString(header)
+
+	paths := make([]string, 0, len(imports))
+	for p := range imports {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	b.WriteString("\nimport (\n")
+	for _, p := range paths {
+		fmt.Fprintf(&b, "\t%q\n", p)
+	}
+	b.WriteString(")\n")
+
+	b.WriteString("\nvar _ gomono.Gomono = (*Mock)(nil)\n")
+	b.WriteString("\n// Mock implements gomono.Gomono. Set the Func field of a method to stub it.\n")
+	b.WriteString("type Mock struct {\n\trecorder\n\n")
+	b.Write(fields.Bytes())
+	b.WriteString("}\n")
+	b.Write(methods.Bytes())
+
+	return format.Source(b.Bytes())
+}
+
+func signatureParams(t reflect.Type) (string, []string) {
+	var params, args []string
+	for i := 0; i < t.NumIn(); i++ {
+		in := t.In(i)
+		name := fmt.Sprintf("arg%v", i)
+		if in == contextType {
+			name = "ctx"
+		}
+
+		typ := in.String()
+		arg := name
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			typ = "..." + in.Elem().String()
+			arg += "..."
+		}
+
+		params = append(params, name+" "+typ)
+		args = append(args, arg)
+	}
+	return strings.Join(params, ", "), args
+}
+
+func signatureResults(t reflect.Type) string {
+	var results []string
+	for i := 0; i < t.NumOut(); i++ {
+		results = append(results, t.Out(i).String())
+	}
+
+	if len(results) > 1 {
+		return "(" + strings.Join(results, ", ") + ")"
+	}
+	return strings.Join(results, "")
+}
+
+//notStubbedResults returns zero values along with an ErrNotStubbed error, when the method returns one.
+//Iterators report ErrNotStubbed from their Err method instead.
+func notStubbedResults(t reflect.Type, method string) string {
+	var results []string
+	for i := 0; i < t.NumOut(); i++ {
+		out := t.Out(i)
+		if out == errorType {
+			results = append(results, fmt.Sprintf("notStubbed(%q)", method))
+			continue
+		}
+		if out == iteratorType {
+			results = append(results, fmt.Sprintf("gomono.NewTransactionsIterator(nil, notStubbed(%q))", method))
+			continue
+		}
+		results = append(results, zero(out))
+	}
+	return strings.Join(results, ", ")
+}
+
+func zero(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		return "nil"
+	case reflect.String:
+		return `""`
+	case reflect.Bool:
+		return "false"
+	case reflect.Struct, reflect.Array:
+		return t.String() + "{}"
+	}
+	return "0"
+}
+
+func collectImports(t reflect.Type, imports map[string]bool) {
+	if t.PkgPath() != "" {
+		imports[t.PkgPath()] = true
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Chan:
+		collectImports(t.Elem(), imports)
+	case reflect.Map:
+		collectImports(t.Key(), imports)
+		collectImports(t.Elem(), imports)
+	case reflect.Func:
+		for i := 0; i < t.NumIn(); i++ {
+			collectImports(t.In(i), imports)
+		}
+		for i := 0; i < t.NumOut(); i++ {
+			collectImports(t.Out(i), imports)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+//TestGenerate_UpToDate fails when gomono.Gomono changed without regenerating the mock
+func TestGenerate_UpToDate(t *testing.T) {
+	want, err := generate()
+	assert.Nil(t, err)
+
+	got, err := ioutil.ReadFile("../../mock.go")
+	assert.Nil(t, err)
+	assert.Equal(t, string(want), string(got), "gomonomock/mock.go is out of date, run go generate ./gomonomock")
+}
@@ -0,0 +1,402 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+
+// Code generated by go run ./internal/gen; DO NOT EDIT.
+
+package gomonomock
+
+import (
+	"context"
+	"github.com/jcobhams/gomono"
+	"io"
+	"time"
+)
+
+var _ gomono.Gomono = (*Mock)(nil)
+
+// Mock implements gomono.Gomono. Set the Func field of a method to stub it.
+type Mock struct {
+	recorder
+
+	AllTransactionsFunc              func(arg0 string, arg1 time.Time, arg2 time.Time, arg3 string, arg4 string, arg5 int) ([]gomono.Transaction, error)
+	AllTransactionsContextFunc       func(ctx context.Context, arg1 string, arg2 time.Time, arg3 time.Time, arg4 string, arg5 string, arg6 int) ([]gomono.Transaction, error)
+	CreditTransactionsFunc           func(arg0 string) (*gomono.TransactionByTypeResponse, error)
+	CreditTransactionsContextFunc    func(ctx context.Context, arg1 string) (*gomono.TransactionByTypeResponse, error)
+	DebitTransactionsFunc            func(arg0 string) (*gomono.TransactionByTypeResponse, error)
+	DebitTransactionsContextFunc     func(ctx context.Context, arg1 string) (*gomono.TransactionByTypeResponse, error)
+	DownloadStatementFunc            func(ctx context.Context, arg1 *gomono.StatementResponsePdf, arg2 io.Writer) (int64, error)
+	DownloadStatementToFileFunc      func(ctx context.Context, arg1 *gomono.StatementResponsePdf, arg2 string) error
+	EnvironmentFunc                  func() gomono.Environment
+	ExchangeTokenFunc                func(arg0 string) (string, error)
+	ExchangeTokenContextFunc         func(ctx context.Context, arg1 string) (string, error)
+	IdentityFunc                     func(arg0 string) (*gomono.IdentityResponse, error)
+	IdentityContextFunc              func(ctx context.Context, arg1 string) (*gomono.IdentityResponse, error)
+	IncomeFunc                       func(arg0 string) (*gomono.IncomeResponse, error)
+	IncomeContextFunc                func(ctx context.Context, arg1 string) (*gomono.IncomeResponse, error)
+	InformationFunc                  func(arg0 string) (*gomono.InformationResponse, error)
+	InformationContextFunc           func(ctx context.Context, arg1 string) (*gomono.InformationResponse, error)
+	InstitutionsFunc                 func() (*gomono.InstitutionsResponse, error)
+	InstitutionsContextFunc          func(ctx context.Context) (*gomono.InstitutionsResponse, error)
+	LookupBVNFunc                    func(arg0 string) (*gomono.IdentityResponse, error)
+	LookupBVNContextFunc             func(ctx context.Context, arg1 string) (*gomono.IdentityResponse, error)
+	PdfStatementJobStatusFunc        func(arg0 string, arg1 string) (*gomono.StatementResponsePdf, error)
+	PdfStatementJobStatusContextFunc func(ctx context.Context, arg1 string, arg2 string) (*gomono.StatementResponsePdf, error)
+	ReauthoriseFunc                  func(arg0 string) (*gomono.ReauthoriseResponse, error)
+	ReauthoriseContextFunc           func(ctx context.Context, arg1 string) (*gomono.ReauthoriseResponse, error)
+	StatementFunc                    func(arg0 string, arg1 string, arg2 string) (*gomono.StatementResponse, error)
+	StatementContextFunc             func(ctx context.Context, arg1 string, arg2 string, arg3 string) (*gomono.StatementResponse, error)
+	SyncFunc                         func(arg0 string) (*gomono.SyncResponse, error)
+	SyncContextFunc                  func(ctx context.Context, arg1 string) (*gomono.SyncResponse, error)
+	TransactionsFunc                 func(arg0 string, arg1 time.Time, arg2 time.Time, arg3 string, arg4 string, arg5 bool) (*gomono.TransactionsResponse, error)
+	TransactionsContextFunc          func(ctx context.Context, arg1 string, arg2 time.Time, arg3 time.Time, arg4 string, arg5 string, arg6 bool) (*gomono.TransactionsResponse, error)
+	TransactionsIteratorFunc         func(arg0 string, arg1 time.Time, arg2 time.Time, arg3 string, arg4 string, arg5 int) *gomono.TransactionsIterator
+	TransactionsIteratorContextFunc  func(ctx context.Context, arg1 string, arg2 time.Time, arg3 time.Time, arg4 string, arg5 string, arg6 int) *gomono.TransactionsIterator
+	TransactionsWithQueryFunc        func(arg0 string, arg1 gomono.TransactionsQuery) (*gomono.TransactionsResponse, error)
+	TransactionsWithQueryContextFunc func(ctx context.Context, arg1 string, arg2 gomono.TransactionsQuery) (*gomono.TransactionsResponse, error)
+	UnlinkFunc                       func(arg0 string) (*gomono.UnlinkResponse, error)
+	UnlinkContextFunc                func(ctx context.Context, arg1 string) (*gomono.UnlinkResponse, error)
+	WaitForPdfStatementFunc          func(ctx context.Context, arg1 string, arg2 string, arg3 gomono.PollOptions) (*gomono.StatementResponsePdf, error)
+}
+
+// AllTransactions records the call and runs AllTransactionsFunc
+func (m *Mock) AllTransactions(arg0 string, arg1 time.Time, arg2 time.Time, arg3 string, arg4 string, arg5 int) ([]gomono.Transaction, error) {
+	m.record("AllTransactions", arg0, arg1, arg2, arg3, arg4, arg5)
+	if m.AllTransactionsFunc == nil {
+		return nil, notStubbed("AllTransactions")
+	}
+	return m.AllTransactionsFunc(arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// AllTransactionsContext records the call and runs AllTransactionsContextFunc
+func (m *Mock) AllTransactionsContext(ctx context.Context, arg1 string, arg2 time.Time, arg3 time.Time, arg4 string, arg5 string, arg6 int) ([]gomono.Transaction, error) {
+	m.record("AllTransactionsContext", ctx, arg1, arg2, arg3, arg4, arg5, arg6)
+	if m.AllTransactionsContextFunc == nil {
+		return nil, notStubbed("AllTransactionsContext")
+	}
+	return m.AllTransactionsContextFunc(ctx, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// CreditTransactions records the call and runs CreditTransactionsFunc
+func (m *Mock) CreditTransactions(arg0 string) (*gomono.TransactionByTypeResponse, error) {
+	m.record("CreditTransactions", arg0)
+	if m.CreditTransactionsFunc == nil {
+		return nil, notStubbed("CreditTransactions")
+	}
+	return m.CreditTransactionsFunc(arg0)
+}
+
+// CreditTransactionsContext records the call and runs CreditTransactionsContextFunc
+func (m *Mock) CreditTransactionsContext(ctx context.Context, arg1 string) (*gomono.TransactionByTypeResponse, error) {
+	m.record("CreditTransactionsContext", ctx, arg1)
+	if m.CreditTransactionsContextFunc == nil {
+		return nil, notStubbed("CreditTransactionsContext")
+	}
+	return m.CreditTransactionsContextFunc(ctx, arg1)
+}
+
+// DebitTransactions records the call and runs DebitTransactionsFunc
+func (m *Mock) DebitTransactions(arg0 string) (*gomono.TransactionByTypeResponse, error) {
+	m.record("DebitTransactions", arg0)
+	if m.DebitTransactionsFunc == nil {
+		return nil, notStubbed("DebitTransactions")
+	}
+	return m.DebitTransactionsFunc(arg0)
+}
+
+// DebitTransactionsContext records the call and runs DebitTransactionsContextFunc
+func (m *Mock) DebitTransactionsContext(ctx context.Context, arg1 string) (*gomono.TransactionByTypeResponse, error) {
+	m.record("DebitTransactionsContext", ctx, arg1)
+	if m.DebitTransactionsContextFunc == nil {
+		return nil, notStubbed("DebitTransactionsContext")
+	}
+	return m.DebitTransactionsContextFunc(ctx, arg1)
+}
+
+// DownloadStatement records the call and runs DownloadStatementFunc
+func (m *Mock) DownloadStatement(ctx context.Context, arg1 *gomono.StatementResponsePdf, arg2 io.Writer) (int64, error) {
+	m.record("DownloadStatement", ctx, arg1, arg2)
+	if m.DownloadStatementFunc == nil {
+		return 0, notStubbed("DownloadStatement")
+	}
+	return m.DownloadStatementFunc(ctx, arg1, arg2)
+}
+
+// DownloadStatementToFile records the call and runs DownloadStatementToFileFunc
+func (m *Mock) DownloadStatementToFile(ctx context.Context, arg1 *gomono.StatementResponsePdf, arg2 string) error {
+	m.record("DownloadStatementToFile", ctx, arg1, arg2)
+	if m.DownloadStatementToFileFunc == nil {
+		return notStubbed("DownloadStatementToFile")
+	}
+	return m.DownloadStatementToFileFunc(ctx, arg1, arg2)
+}
+
+// Environment records the call and runs EnvironmentFunc
+func (m *Mock) Environment() gomono.Environment {
+	m.record("Environment")
+	if m.EnvironmentFunc == nil {
+		return ""
+	}
+	return m.EnvironmentFunc()
+}
+
+// ExchangeToken records the call and runs ExchangeTokenFunc
+func (m *Mock) ExchangeToken(arg0 string) (string, error) {
+	m.record("ExchangeToken", arg0)
+	if m.ExchangeTokenFunc == nil {
+		return "", notStubbed("ExchangeToken")
+	}
+	return m.ExchangeTokenFunc(arg0)
+}
+
+// ExchangeTokenContext records the call and runs ExchangeTokenContextFunc
+func (m *Mock) ExchangeTokenContext(ctx context.Context, arg1 string) (string, error) {
+	m.record("ExchangeTokenContext", ctx, arg1)
+	if m.ExchangeTokenContextFunc == nil {
+		return "", notStubbed("ExchangeTokenContext")
+	}
+	return m.ExchangeTokenContextFunc(ctx, arg1)
+}
+
+// Identity records the call and runs IdentityFunc
+func (m *Mock) Identity(arg0 string) (*gomono.IdentityResponse, error) {
+	m.record("Identity", arg0)
+	if m.IdentityFunc == nil {
+		return nil, notStubbed("Identity")
+	}
+	return m.IdentityFunc(arg0)
+}
+
+// IdentityContext records the call and runs IdentityContextFunc
+func (m *Mock) IdentityContext(ctx context.Context, arg1 string) (*gomono.IdentityResponse, error) {
+	m.record("IdentityContext", ctx, arg1)
+	if m.IdentityContextFunc == nil {
+		return nil, notStubbed("IdentityContext")
+	}
+	return m.IdentityContextFunc(ctx, arg1)
+}
+
+// Income records the call and runs IncomeFunc
+func (m *Mock) Income(arg0 string) (*gomono.IncomeResponse, error) {
+	m.record("Income", arg0)
+	if m.IncomeFunc == nil {
+		return nil, notStubbed("Income")
+	}
+	return m.IncomeFunc(arg0)
+}
+
+// IncomeContext records the call and runs IncomeContextFunc
+func (m *Mock) IncomeContext(ctx context.Context, arg1 string) (*gomono.IncomeResponse, error) {
+	m.record("IncomeContext", ctx, arg1)
+	if m.IncomeContextFunc == nil {
+		return nil, notStubbed("IncomeContext")
+	}
+	return m.IncomeContextFunc(ctx, arg1)
+}
+
+// Information records the call and runs InformationFunc
+func (m *Mock) Information(arg0 string) (*gomono.InformationResponse, error) {
+	m.record("Information", arg0)
+	if m.InformationFunc == nil {
+		return nil, notStubbed("Information")
+	}
+	return m.InformationFunc(arg0)
+}
+
+// InformationContext records the call and runs InformationContextFunc
+func (m *Mock) InformationContext(ctx context.Context, arg1 string) (*gomono.InformationResponse, error) {
+	m.record("InformationContext", ctx, arg1)
+	if m.InformationContextFunc == nil {
+		return nil, notStubbed("InformationContext")
+	}
+	return m.InformationContextFunc(ctx, arg1)
+}
+
+// Institutions records the call and runs InstitutionsFunc
+func (m *Mock) Institutions() (*gomono.InstitutionsResponse, error) {
+	m.record("Institutions")
+	if m.InstitutionsFunc == nil {
+		return nil, notStubbed("Institutions")
+	}
+	return m.InstitutionsFunc()
+}
+
+// InstitutionsContext records the call and runs InstitutionsContextFunc
+func (m *Mock) InstitutionsContext(ctx context.Context) (*gomono.InstitutionsResponse, error) {
+	m.record("InstitutionsContext", ctx)
+	if m.InstitutionsContextFunc == nil {
+		return nil, notStubbed("InstitutionsContext")
+	}
+	return m.InstitutionsContextFunc(ctx)
+}
+
+// LookupBVN records the call and runs LookupBVNFunc
+func (m *Mock) LookupBVN(arg0 string) (*gomono.IdentityResponse, error) {
+	m.record("LookupBVN", arg0)
+	if m.LookupBVNFunc == nil {
+		return nil, notStubbed("LookupBVN")
+	}
+	return m.LookupBVNFunc(arg0)
+}
+
+// LookupBVNContext records the call and runs LookupBVNContextFunc
+func (m *Mock) LookupBVNContext(ctx context.Context, arg1 string) (*gomono.IdentityResponse, error) {
+	m.record("LookupBVNContext", ctx, arg1)
+	if m.LookupBVNContextFunc == nil {
+		return nil, notStubbed("LookupBVNContext")
+	}
+	return m.LookupBVNContextFunc(ctx, arg1)
+}
+
+// PdfStatementJobStatus records the call and runs PdfStatementJobStatusFunc
+func (m *Mock) PdfStatementJobStatus(arg0 string, arg1 string) (*gomono.StatementResponsePdf, error) {
+	m.record("PdfStatementJobStatus", arg0, arg1)
+	if m.PdfStatementJobStatusFunc == nil {
+		return nil, notStubbed("PdfStatementJobStatus")
+	}
+	return m.PdfStatementJobStatusFunc(arg0, arg1)
+}
+
+// PdfStatementJobStatusContext records the call and runs PdfStatementJobStatusContextFunc
+func (m *Mock) PdfStatementJobStatusContext(ctx context.Context, arg1 string, arg2 string) (*gomono.StatementResponsePdf, error) {
+	m.record("PdfStatementJobStatusContext", ctx, arg1, arg2)
+	if m.PdfStatementJobStatusContextFunc == nil {
+		return nil, notStubbed("PdfStatementJobStatusContext")
+	}
+	return m.PdfStatementJobStatusContextFunc(ctx, arg1, arg2)
+}
+
+// Reauthorise records the call and runs ReauthoriseFunc
+func (m *Mock) Reauthorise(arg0 string) (*gomono.ReauthoriseResponse, error) {
+	m.record("Reauthorise", arg0)
+	if m.ReauthoriseFunc == nil {
+		return nil, notStubbed("Reauthorise")
+	}
+	return m.ReauthoriseFunc(arg0)
+}
+
+// ReauthoriseContext records the call and runs ReauthoriseContextFunc
+func (m *Mock) ReauthoriseContext(ctx context.Context, arg1 string) (*gomono.ReauthoriseResponse, error) {
+	m.record("ReauthoriseContext", ctx, arg1)
+	if m.ReauthoriseContextFunc == nil {
+		return nil, notStubbed("ReauthoriseContext")
+	}
+	return m.ReauthoriseContextFunc(ctx, arg1)
+}
+
+// Statement records the call and runs StatementFunc
+func (m *Mock) Statement(arg0 string, arg1 string, arg2 string) (*gomono.StatementResponse, error) {
+	m.record("Statement", arg0, arg1, arg2)
+	if m.StatementFunc == nil {
+		return nil, notStubbed("Statement")
+	}
+	return m.StatementFunc(arg0, arg1, arg2)
+}
+
+// StatementContext records the call and runs StatementContextFunc
+func (m *Mock) StatementContext(ctx context.Context, arg1 string, arg2 string, arg3 string) (*gomono.StatementResponse, error) {
+	m.record("StatementContext", ctx, arg1, arg2, arg3)
+	if m.StatementContextFunc == nil {
+		return nil, notStubbed("StatementContext")
+	}
+	return m.StatementContextFunc(ctx, arg1, arg2, arg3)
+}
+
+// Sync records the call and runs SyncFunc
+func (m *Mock) Sync(arg0 string) (*gomono.SyncResponse, error) {
+	m.record("Sync", arg0)
+	if m.SyncFunc == nil {
+		return nil, notStubbed("Sync")
+	}
+	return m.SyncFunc(arg0)
+}
+
+// SyncContext records the call and runs SyncContextFunc
+func (m *Mock) SyncContext(ctx context.Context, arg1 string) (*gomono.SyncResponse, error) {
+	m.record("SyncContext", ctx, arg1)
+	if m.SyncContextFunc == nil {
+		return nil, notStubbed("SyncContext")
+	}
+	return m.SyncContextFunc(ctx, arg1)
+}
+
+// Transactions records the call and runs TransactionsFunc
+func (m *Mock) Transactions(arg0 string, arg1 time.Time, arg2 time.Time, arg3 string, arg4 string, arg5 bool) (*gomono.TransactionsResponse, error) {
+	m.record("Transactions", arg0, arg1, arg2, arg3, arg4, arg5)
+	if m.TransactionsFunc == nil {
+		return nil, notStubbed("Transactions")
+	}
+	return m.TransactionsFunc(arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// TransactionsContext records the call and runs TransactionsContextFunc
+func (m *Mock) TransactionsContext(ctx context.Context, arg1 string, arg2 time.Time, arg3 time.Time, arg4 string, arg5 string, arg6 bool) (*gomono.TransactionsResponse, error) {
+	m.record("TransactionsContext", ctx, arg1, arg2, arg3, arg4, arg5, arg6)
+	if m.TransactionsContextFunc == nil {
+		return nil, notStubbed("TransactionsContext")
+	}
+	return m.TransactionsContextFunc(ctx, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// TransactionsIterator records the call and runs TransactionsIteratorFunc
+func (m *Mock) TransactionsIterator(arg0 string, arg1 time.Time, arg2 time.Time, arg3 string, arg4 string, arg5 int) *gomono.TransactionsIterator {
+	m.record("TransactionsIterator", arg0, arg1, arg2, arg3, arg4, arg5)
+	if m.TransactionsIteratorFunc == nil {
+		return gomono.NewTransactionsIterator(nil, notStubbed("TransactionsIterator"))
+	}
+	return m.TransactionsIteratorFunc(arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// TransactionsIteratorContext records the call and runs TransactionsIteratorContextFunc
+func (m *Mock) TransactionsIteratorContext(ctx context.Context, arg1 string, arg2 time.Time, arg3 time.Time, arg4 string, arg5 string, arg6 int) *gomono.TransactionsIterator {
+	m.record("TransactionsIteratorContext", ctx, arg1, arg2, arg3, arg4, arg5, arg6)
+	if m.TransactionsIteratorContextFunc == nil {
+		return gomono.NewTransactionsIterator(nil, notStubbed("TransactionsIteratorContext"))
+	}
+	return m.TransactionsIteratorContextFunc(ctx, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// TransactionsWithQuery records the call and runs TransactionsWithQueryFunc
+func (m *Mock) TransactionsWithQuery(arg0 string, arg1 gomono.TransactionsQuery) (*gomono.TransactionsResponse, error) {
+	m.record("TransactionsWithQuery", arg0, arg1)
+	if m.TransactionsWithQueryFunc == nil {
+		return nil, notStubbed("TransactionsWithQuery")
+	}
+	return m.TransactionsWithQueryFunc(arg0, arg1)
+}
+
+// TransactionsWithQueryContext records the call and runs TransactionsWithQueryContextFunc
+func (m *Mock) TransactionsWithQueryContext(ctx context.Context, arg1 string, arg2 gomono.TransactionsQuery) (*gomono.TransactionsResponse, error) {
+	m.record("TransactionsWithQueryContext", ctx, arg1, arg2)
+	if m.TransactionsWithQueryContextFunc == nil {
+		return nil, notStubbed("TransactionsWithQueryContext")
+	}
+	return m.TransactionsWithQueryContextFunc(ctx, arg1, arg2)
+}
+
+// Unlink records the call and runs UnlinkFunc
+func (m *Mock) Unlink(arg0 string) (*gomono.UnlinkResponse, error) {
+	m.record("Unlink", arg0)
+	if m.UnlinkFunc == nil {
+		return nil, notStubbed("Unlink")
+	}
+	return m.UnlinkFunc(arg0)
+}
+
+// UnlinkContext records the call and runs UnlinkContextFunc
+func (m *Mock) UnlinkContext(ctx context.Context, arg1 string) (*gomono.UnlinkResponse, error) {
+	m.record("UnlinkContext", ctx, arg1)
+	if m.UnlinkContextFunc == nil {
+		return nil, notStubbed("UnlinkContext")
+	}
+	return m.UnlinkContextFunc(ctx, arg1)
+}
+
+// WaitForPdfStatement records the call and runs WaitForPdfStatementFunc
+func (m *Mock) WaitForPdfStatement(ctx context.Context, arg1 string, arg2 string, arg3 gomono.PollOptions) (*gomono.StatementResponsePdf, error) {
+	m.record("WaitForPdfStatement", ctx, arg1, arg2, arg3)
+	if m.WaitForPdfStatementFunc == nil {
+		return nil, notStubbed("WaitForPdfStatement")
+	}
+	return m.WaitForPdfStatementFunc(ctx, arg1, arg2, arg3)
+}
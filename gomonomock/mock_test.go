@@ -0,0 +1,92 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomonomock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jcobhams/gomono"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testT struct {
+	failures []string
+}
+
+func (t *testT) Helper() {}
+
+func (t *testT) Errorf(format string, args ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func TestMock(t *testing.T) {
+	m := &Mock{}
+	m.InformationFunc = func(id string) (*gomono.InformationResponse, error) {
+		var r gomono.InformationResponse
+		r.Account.ID = id
+		return &r, nil
+	}
+
+	var g gomono.Gomono = m
+	r, err := g.Information("acc-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "acc-1", r.Account.ID)
+
+	ctx := context.Background()
+	_, err = g.IncomeContext(ctx, "acc-2")
+	assert.True(t, errors.Is(err, ErrNotStubbed))
+
+	assert.Equal(t, []Call{
+		{Method: "Information", Args: []interface{}{"acc-1"}},
+		{Method: "IncomeContext", Args: []interface{}{ctx, "acc-2"}},
+	}, m.Calls())
+	assert.Len(t, m.CallsTo("Information"), 1)
+	assert.Equal(t, 1, m.CallCount("IncomeContext"))
+
+	ft := &testT{}
+	assert.True(t, m.AssertCalled(ft, "Information", 1))
+	assert.True(t, m.AssertNotCalled(ft, "Sync"))
+	assert.False(t, m.AssertCalled(ft, "Sync", 2))
+	assert.Len(t, ft.failures, 1)
+
+	m.Reset()
+	assert.Empty(t, m.Calls())
+	_, err = g.Information("acc-1")
+	assert.Nil(t, err)
+}
+
+func TestMock_TransactionsIterator(t *testing.T) {
+	m := &Mock{}
+	it := m.TransactionsIterator("acc-1", time.Time{}, time.Time{}, "", "", 0)
+	assert.False(t, it.Next())
+	assert.True(t, errors.Is(it.Err(), ErrNotStubbed))
+
+	m.TransactionsIteratorFunc = func(id string, start, end time.Time, narration, tnxType string, maxPages int) *gomono.TransactionsIterator {
+		return gomono.NewTransactionsIterator([]gomono.Transaction{{ID: "tnx-1"}}, nil)
+	}
+	it = m.TransactionsIterator("acc-1", time.Time{}, time.Time{}, "", "", 0)
+	assert.True(t, it.Next())
+	assert.Equal(t, "tnx-1", it.Transaction().ID)
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Err())
+}
+
+//TestMock_Stubs checks every method of gomono.Gomono can be stubbed through its Func field
+func TestMock_Stubs(t *testing.T) {
+	iface := reflect.TypeOf((*gomono.Gomono)(nil)).Elem()
+	mock := reflect.TypeOf(Mock{})
+
+	for i := 0; i < iface.NumMethod(); i++ {
+		m := iface.Method(i)
+		f, ok := mock.FieldByName(m.Name + "Func")
+		if assert.True(t, ok, m.Name) {
+			assert.Equal(t, m.Type, f.Type, m.Name)
+		}
+	}
+}
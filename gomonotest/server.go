@@ -0,0 +1,577 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+//Package gomonotest provides an in-process fake of the Mono API to test code built on gomono.
+//
+//	s := gomonotest.NewServer()
+//	defer s.Close()
+//
+//	s.AddAccount(gomonotest.Account{Account: gomono.Account{ID: "acc-1", Name: "ADA OBI"}})
+//	s.AddCode("connect-code", "acc-1")
+//	s.AddTransactions("acc-1", gomono.Transaction{Amount: gomono.NewMoney(10000, ""), Type: "debit"})
+//
+//	gm, _ := gomono.New(s.Config())
+package gomonotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jcobhams/gomono"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//SecretKey is the secret key the server accepts unless Server.SecretKey is changed
+const SecretKey = "test_sk_gomonotest"
+
+//DefaultPageSize is the number of transactions per page unless a limit is asked for
+const DefaultPageSize = 20
+
+//pdfBody is served for every built PDF statement
+const pdfBody = "%PDF-1.4\n%gomonotest\n%%EOF\n"
+
+type (
+	//Server is a fake Mono API keeping its accounts in memory. It is safe for concurrent use.
+	//
+	//Calls are answered like Mono would: unknown accounts get a 404, accounts needing reauthorisation
+	//a REAUTHORISATION_REQUIRED error and unlinked accounts an "already been unlinked" error.
+	Server struct {
+		*httptest.Server
+
+		//SecretKey is the mono-sec-key calls must carry, see Config
+		SecretKey string
+		//PageSize is the number of transactions per page, DefaultPageSize when zero
+		PageSize int
+		//StatementPolls is the number of job status polls a PDF statement stays BUILDING for
+		StatementPolls int
+
+		mu           sync.Mutex
+		codes        map[string]string
+		accounts     map[string]*account
+		institutions []gomono.Institution
+		bvns         map[string]gomono.IdentityResponse
+		failures     map[string][]failure
+		calls        map[string]int
+		jobs         map[string]*job
+		nextID       int
+	}
+
+	//Account is an account registered with the server. Identity and Income are answered with a 404 when nil.
+	Account struct {
+		gomono.Account
+		//DataStatus is reported by Information, "AVAILABLE" when empty
+		DataStatus   string
+		Identity     *gomono.IdentityResponse
+		Income       *gomono.IncomeResponse
+		Transactions []gomono.Transaction
+	}
+
+	account struct {
+		Account
+		reauthRequired   bool
+		unlinked         bool
+		failedStatements bool
+	}
+
+	failure struct {
+		status int
+		body   string
+	}
+
+	job struct {
+		id        string
+		accountID string
+		polls     int
+		failed    bool
+	}
+)
+
+//NewServer starts a fake Mono API. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		SecretKey: SecretKey,
+		codes:     make(map[string]string),
+		accounts:  make(map[string]*account),
+		bvns:      make(map[string]gomono.IdentityResponse),
+		failures:  make(map[string][]failure),
+		calls:     make(map[string]int),
+		jobs:      make(map[string]*job),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+//Config returns a gomono Config pointed at the server, without retries
+func (s *Server) Config() gomono.Config {
+	return gomono.Config{
+		SecretKey:  s.SecretKey,
+		HttpClient: s.Client(),
+		ApiUrl:     s.URL,
+	}
+}
+
+//AddAccount registers an account, replacing any account with the same ID
+func (s *Server) AddAccount(a Account) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a.DataStatus == "" {
+		a.DataStatus = "AVAILABLE"
+	}
+	a.Transactions = s.withIDs(a.Transactions)
+	s.accounts[a.ID] = &account{Account: a}
+}
+
+//AddCode makes ExchangeToken exchange the Mono Connect code for the account ID
+func (s *Server) AddCode(code, accountID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.codes[code] = accountID
+}
+
+//AddTransactions appends transactions to an account. Transactions without an ID are given one.
+func (s *Server) AddTransactions(accountID string, tnxs ...gomono.Transaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a, ok := s.accounts[accountID]; ok {
+		a.Transactions = append(a.Transactions, s.withIDs(tnxs)...)
+	}
+}
+
+//AddInstitutions adds institutions to the coverage list
+func (s *Server) AddInstitutions(institutions ...gomono.Institution) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.institutions = append(s.institutions, institutions...)
+}
+
+//AddBVN makes LookupBVN answer with the identity for the BVN
+func (s *Server) AddBVN(bvn string, identity gomono.IdentityResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bvns[bvn] = identity
+}
+
+//RequireReauth puts an account in the reauthorisation required state, as if its user changed their bank password
+func (s *Server) RequireReauth(accountID string) {
+	s.setState(accountID, func(a *account) { a.reauthRequired = true })
+}
+
+//CompleteReauth takes an account out of the reauthorisation required state
+func (s *Server) CompleteReauth(accountID string) {
+	s.setState(accountID, func(a *account) { a.reauthRequired = false })
+}
+
+//FailStatements makes the PDF statements of an account end up FAILED
+func (s *Server) FailStatements(accountID string) {
+	s.setState(accountID, func(a *account) { a.failedStatements = true })
+}
+
+//Fail makes the next n calls to an endpoint, named after its Gomono method (e.g. "Transactions"),
+//answer with status and body instead
+func (s *Server) Fail(endpoint string, n, status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := 0; i < n; i++ {
+		s.failures[endpoint] = append(s.failures[endpoint], failure{status: status, body: body})
+	}
+}
+
+//Calls returns how many calls were made to an endpoint, named after its Gomono method
+func (s *Server) Calls(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[endpoint]
+}
+
+func (s *Server) setState(accountID string, f func(a *account)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a, ok := s.accounts[accountID]; ok {
+		f(a)
+	}
+}
+
+func (s *Server) withIDs(tnxs []gomono.Transaction) []gomono.Transaction {
+	out := make([]gomono.Transaction, len(tnxs))
+	for i, t := range tnxs {
+		if t.ID == "" {
+			s.nextID++
+			t.ID = fmt.Sprintf("tnx-%v", s.nextID)
+		}
+		out[i] = t
+	}
+	return out
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	endpoint, params := route(r.URL.Path)
+	if endpoint == "" {
+		writeError(w, http.StatusNotFound, "Route not found", "")
+		return
+	}
+	s.calls[endpoint]++
+
+	//statements are downloaded from the path Mono hands out, without the secret key
+	if endpoint != "DownloadStatement" && r.Header.Get("mono-sec-key") != s.SecretKey {
+		writeError(w, http.StatusUnauthorized, "Invalid secret key", "")
+		return
+	}
+
+	if f := s.failures[endpoint]; len(f) > 0 {
+		s.failures[endpoint] = f[1:]
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(f[0].status)
+		fmt.Fprint(w, f[0].body)
+		return
+	}
+
+	switch endpoint {
+	case "ExchangeToken":
+		s.exchangeToken(w, r)
+	case "Institutions":
+		writeJSON(w, append([]gomono.Institution{}, s.institutions...))
+	case "LookupBVN":
+		s.lookupBVN(w, r)
+	case "DownloadStatement":
+		s.downloadStatement(w, params[0])
+	default:
+		s.serveAccount(w, r, endpoint, params)
+	}
+}
+
+func (s *Server) serveAccount(w http.ResponseWriter, r *http.Request, endpoint string, params []string) {
+	a, ok := s.accounts[params[0]]
+	switch {
+	case !ok:
+		writeError(w, http.StatusNotFound, "Account not found", "NOT_FOUND")
+		return
+	case a.unlinked:
+		writeError(w, http.StatusBadRequest, "This account has already been unlinked", "ACCOUNT_UNLINKED")
+		return
+	case a.reauthRequired && endpoint == "Sync":
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"status":  gomono.SyncStatusFailed,
+			"message": "Reauthorisation required",
+			"code":    gomono.SyncCodeReauthRequired,
+			"token":   reauthToken(a.ID),
+		})
+		return
+	case a.reauthRequired && endpoint != "Reauthorise" && endpoint != "Unlink":
+		writeError(w, http.StatusBadRequest, "Reauthorisation required", gomono.SyncCodeReauthRequired)
+		return
+	}
+
+	switch endpoint {
+	case "Information":
+		var resp gomono.InformationResponse
+		resp.Meta.DataStatus = a.DataStatus
+		resp.Account = a.Account.Account
+		writeJSON(w, resp)
+
+	case "Statement":
+		s.statement(w, r, a)
+
+	case "PdfStatementJobStatus":
+		j, ok := s.jobs[params[1]]
+		if !ok || j.accountID != a.ID {
+			writeError(w, http.StatusNotFound, "Job not found", "NOT_FOUND")
+			return
+		}
+		j.polls++
+		writeJSON(w, s.jobStatus(j))
+
+	case "Sync":
+		writeJSON(w, gomono.SyncResponse{Status: gomono.SyncStatusSuccessful, Code: gomono.SyncCodeSuccessful})
+
+	case "Reauthorise":
+		writeJSON(w, gomono.ReauthoriseResponse{Token: reauthToken(a.ID)})
+
+	case "Unlink":
+		a.unlinked = true
+		writeJSON(w, gomono.UnlinkResponse{Message: "Account unlinked successfully"})
+
+	case "Transactions":
+		s.transactions(w, r, a)
+
+	case "CreditTransactions":
+		writeJSON(w, totals(a.Transactions, "credit"))
+
+	case "DebitTransactions":
+		writeJSON(w, totals(a.Transactions, "debit"))
+
+	case "Income":
+		if a.Income == nil {
+			writeError(w, http.StatusNotFound, "Income not available", "NOT_FOUND")
+			return
+		}
+		writeJSON(w, a.Income)
+
+	case "Identity":
+		if a.Identity == nil {
+			writeError(w, http.StatusNotFound, "Identity not available", "NOT_FOUND")
+			return
+		}
+		writeJSON(w, a.Identity)
+	}
+}
+
+func (s *Server) exchangeToken(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Code string `json:"code"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	id, ok := s.codes[body.Code]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Invalid code", "")
+		return
+	}
+	writeJSON(w, map[string]string{"id": id})
+}
+
+func (s *Server) lookupBVN(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		BVN string `json:"bvn"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	identity, ok := s.bvns[body.BVN]
+	if !ok {
+		writeError(w, http.StatusNotFound, "BVN not found", "NOT_FOUND")
+		return
+	}
+	writeJSON(w, identity)
+}
+
+func (s *Server) statement(w http.ResponseWriter, r *http.Request, a *account) {
+	q := r.URL.Query()
+
+	if q.Get("output") == "pdf" {
+		s.nextID++
+		j := &job{id: fmt.Sprintf("job-%v", s.nextID), accountID: a.ID, failed: a.failedStatements}
+		s.jobs[j.id] = j
+		writeJSON(w, s.jobStatus(j))
+		return
+	}
+
+	var since time.Time
+	period := strings.TrimSuffix(strings.TrimPrefix(q.Get("period"), "last"), "months")
+	if months, err := strconv.Atoi(period); err == nil {
+		since = time.Now().AddDate(0, -months, 0)
+	}
+
+	var resp gomono.StatementResponseJson
+	for _, t := range a.Transactions {
+		if t.Date.Before(since) {
+			continue
+		}
+		resp.Data = append(resp.Data, gomono.StatementEntry{
+			ID:        t.ID,
+			Type:      t.Type,
+			Date:      t.Date,
+			Narration: t.Narration,
+			Amount:    t.Amount,
+			Balance:   t.Balance,
+		})
+	}
+	resp.Meta.Count = len(resp.Data)
+	writeJSON(w, resp)
+}
+
+func (s *Server) jobStatus(j *job) gomono.StatementResponsePdf {
+	resp := gomono.StatementResponsePdf{ID: j.id, Status: gomono.StatementStatusBuilding}
+	switch {
+	case j.polls > s.StatementPolls && j.failed:
+		resp.Status = gomono.StatementStatusFailed
+	case j.polls > s.StatementPolls:
+		resp.Status = gomono.StatementStatusBuilt
+		resp.Path = fmt.Sprintf("%v/statements/%v.pdf", s.URL, j.id)
+	}
+	return resp
+}
+
+func (s *Server) downloadStatement(w http.ResponseWriter, file string) {
+	j, ok := s.jobs[strings.TrimSuffix(file, ".pdf")]
+	if !ok || !s.jobStatus(j).Built() {
+		writeError(w, http.StatusNotFound, "Statement not found", "NOT_FOUND")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdfBody)))
+	io.WriteString(w, pdfBody)
+}
+
+func (s *Server) transactions(w http.ResponseWriter, r *http.Request, a *account) {
+	q := r.URL.Query()
+
+	start, err := parseDate(q.Get("start"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid start date", "")
+		return
+	}
+
+	end, err := parseDate(q.Get("end"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid end date", "")
+		return
+	}
+
+	var matched []gomono.Transaction
+	for _, t := range a.Transactions {
+		switch {
+		case !start.IsZero() && t.Date.Before(start):
+		case !end.IsZero() && !t.Date.Before(end.AddDate(0, 0, 1)):
+		case q.Get("type") != "" && !strings.EqualFold(t.Type, q.Get("type")):
+		case q.Get("narration") != "" && !strings.Contains(strings.ToLower(t.Narration), strings.ToLower(q.Get("narration"))):
+		default:
+			matched = append(matched, t)
+		}
+	}
+
+	var resp gomono.TransactionsResponse
+	resp.Paging.Total = len(matched)
+	resp.Paging.Page = 1
+	resp.Data = matched
+
+	if q.Get("paginate") != "false" {
+		size := s.PageSize
+		if limit, err := strconv.Atoi(q.Get("limit")); err == nil && limit > 0 {
+			size = limit
+		}
+		if size <= 0 {
+			size = DefaultPageSize
+		}
+
+		page, _ := strconv.Atoi(q.Get("page"))
+		if page < 1 {
+			page = 1
+		}
+
+		from, to := (page-1)*size, page*size
+		if from > len(matched) {
+			from = len(matched)
+		}
+		if to > len(matched) {
+			to = len(matched)
+		}
+
+		resp.Paging.Page = page
+		resp.Data = matched[from:to]
+		if page > 1 {
+			resp.Paging.Previous = fmt.Sprintf("%v/accounts/%v/transactions?page=%v", s.URL, a.ID, page-1)
+		}
+		if to < len(matched) {
+			resp.Paging.Next = fmt.Sprintf("%v/accounts/%v/transactions?page=%v", s.URL, a.ID, page+1)
+		}
+	}
+
+	if resp.Data == nil {
+		resp.Data = []gomono.Transaction{}
+	}
+	writeJSON(w, resp)
+}
+
+//totals adds up the transactions of a type per period (mm-yy), oldest period first
+func totals(tnxs []gomono.Transaction, tnxType string) gomono.TransactionByTypeResponse {
+	var resp gomono.TransactionByTypeResponse
+	byPeriod := make(map[string]int64)
+	var periods []time.Time
+
+	for _, t := range tnxs {
+		if !strings.EqualFold(t.Type, tnxType) {
+			continue
+		}
+
+		month := time.Date(t.Date.Year(), t.Date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		period := month.Format("01-06")
+		if _, ok := byPeriod[period]; !ok {
+			periods = append(periods, month)
+		}
+		byPeriod[period] += t.Amount.Minor
+		resp.Total.Minor += t.Amount.Minor
+	}
+
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Before(periods[j]) })
+	for _, p := range periods {
+		period := p.Format("01-06")
+		resp.History = append(resp.History, gomono.PeriodTotal{Amount: gomono.NewMoney(byPeriod[period], ""), Period: period})
+	}
+	return resp
+}
+
+//route maps a request path to the Gomono method calling it and the path parameters
+func route(path string) (string, []string) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case path == "/account/auth":
+		return "ExchangeToken", nil
+	case path == "/coverage":
+		return "Institutions", nil
+	case path == "/v1/lookup/bvn/identity":
+		return "LookupBVN", nil
+	case len(parts) == 2 && parts[0] == "statements":
+		return "DownloadStatement", parts[1:]
+	case len(parts) < 2 || parts[0] != "accounts":
+		return "", nil
+	case len(parts) == 2:
+		return "Information", parts[1:]
+	case len(parts) == 5 && parts[2] == "statement" && parts[3] == "jobs":
+		return "PdfStatementJobStatus", []string{parts[1], parts[4]}
+	case len(parts) != 3:
+		return "", nil
+	}
+
+	endpoints := map[string]string{
+		"statement":    "Statement",
+		"sync":         "Sync",
+		"reauthorise":  "Reauthorise",
+		"unlink":       "Unlink",
+		"transactions": "Transactions",
+		"credit":       "CreditTransactions",
+		"debit":        "DebitTransactions",
+		"income":       "Income",
+		"identity":     "Identity",
+	}
+	return endpoints[parts[2]], parts[1:2]
+}
+
+func parseDate(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(gomono.DateLayout, v)
+}
+
+func reauthToken(accountID string) string {
+	return "reauth-" + accountID
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"message": message, "code": code})
+}
@@ -0,0 +1,185 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomonotest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/jcobhams/gomono"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var (
+	testAccountId = "acc-1"
+	testCode      = "connect-code"
+	testDay       = time.Date(2020, 10, 5, 0, 0, 0, 0, time.UTC)
+)
+
+func newTestServer(t *testing.T) (*Server, gomono.Gomono) {
+	s := NewServer()
+	s.AddAccount(Account{
+		Account:  gomono.Account{ID: testAccountId, Name: "ADA OBI", Currency: gomono.CurrencyNGN, Balance: gomono.NewMoney(500000, "")},
+		Income:   &gomono.IncomeResponse{Type: "INCOME", Amount: gomono.NewMoney(59700000, ""), Employer: "Relentless Labs Inc"},
+		Identity: &gomono.IdentityResponse{FirstName: "ADA", LastName: "OBI"},
+	})
+	s.AddCode(testCode, testAccountId)
+
+	for i := 0; i < 5; i++ {
+		s.AddTransactions(testAccountId,
+			gomono.Transaction{Amount: gomono.NewMoney(10000, ""), Type: "debit", Narration: "POS PURCHASE", Date: gomono.Date{Time: testDay.AddDate(0, -i, 0)}},
+			gomono.Transaction{Amount: gomono.NewMoney(25000, ""), Type: "credit", Narration: "SALARY", Date: gomono.Date{Time: testDay.AddDate(0, -i, 1)}},
+		)
+	}
+
+	g, err := gomono.New(s.Config())
+	assert.Nil(t, err)
+	return s, g
+}
+
+func TestServer_Accounts(t *testing.T) {
+	s, g := newTestServer(t)
+	defer s.Close()
+
+	id, err := g.ExchangeToken(testCode)
+	assert.Nil(t, err)
+	assert.Equal(t, testAccountId, id)
+
+	_, err = g.ExchangeToken("unknown-code")
+	assert.NotNil(t, err)
+
+	info, err := g.Information(id)
+	assert.Nil(t, err)
+	assert.Equal(t, "AVAILABLE", info.Meta.DataStatus)
+	assert.Equal(t, "ADA OBI", info.Account.Name)
+	assert.Equal(t, gomono.NewMoney(500000, gomono.CurrencyNGN), info.Account.Balance)
+
+	_, err = g.Information("missing")
+	assert.True(t, errors.Is(err, gomono.ErrNotFound))
+
+	income, err := g.Income(id)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(59700000), income.Amount.Minor)
+
+	identity, err := g.Identity(id)
+	assert.Nil(t, err)
+	assert.Equal(t, "ADA", identity.FirstName)
+
+	s.AddInstitutions(gomono.Institution{Name: "GTBank"})
+	institutions, err := g.Institutions()
+	assert.Nil(t, err)
+	assert.Len(t, institutions.Institutions, 1)
+
+	s.AddBVN("12345678901", gomono.IdentityResponse{FirstName: "ADA"})
+	bvn, err := g.LookupBVN("12345678901")
+	assert.Nil(t, err)
+	assert.Equal(t, "ADA", bvn.FirstName)
+
+	bad, _ := gomono.New(gomono.Config{SecretKey: "WRONG", HttpClient: s.Client(), ApiUrl: s.URL})
+	_, err = bad.Information(id)
+	assert.True(t, errors.Is(err, gomono.ErrUnauthorized))
+
+	_, err = g.Unlink(id)
+	assert.Nil(t, err)
+	_, err = g.Unlink(id)
+	assert.True(t, errors.Is(err, gomono.ErrAccountUnlinked))
+}
+
+func TestServer_Transactions(t *testing.T) {
+	s, g := newTestServer(t)
+	defer s.Close()
+	s.PageSize = 4
+
+	r, err := g.Transactions(testAccountId, time.Time{}, time.Time{}, "", "", true)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, r.Paging.Total)
+	assert.Len(t, r.Data, 4)
+	assert.NotEmpty(t, r.Paging.Next)
+
+	all, err := g.AllTransactions(testAccountId, time.Time{}, time.Time{}, "", "", 0)
+	assert.Nil(t, err)
+	assert.Len(t, all, 10)
+	assert.Equal(t, 3, s.Calls("Transactions")-1)
+
+	r, err = g.TransactionsWithQuery(testAccountId, gomono.TransactionsQuery{
+		Start: testDay.AddDate(0, -1, 0),
+		End:   testDay,
+		Type:  gomono.TransactionTypeDebit,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, r.Data, 2)
+
+	r, err = g.Transactions(testAccountId, time.Time{}, time.Time{}, "salary", "", false)
+	assert.Nil(t, err)
+	assert.Len(t, r.Data, 5)
+	assert.Empty(t, r.Paging.Next)
+
+	credit, err := g.CreditTransactions(testAccountId)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(125000), credit.Total.Minor)
+	assert.Len(t, credit.History, 5)
+	assert.Equal(t, "06-20", credit.History[0].Period)
+}
+
+func TestServer_Statements(t *testing.T) {
+	s, g := newTestServer(t)
+	defer s.Close()
+	s.StatementPolls = 2
+
+	stmt, err := g.Statement(testAccountId, "", "json")
+	assert.Nil(t, err)
+	assert.Equal(t, 10, stmt.JSON.Meta.Count)
+
+	stmt, err = g.Statement(testAccountId, "last6months", "pdf")
+	assert.Nil(t, err)
+	assert.Equal(t, gomono.StatementStatusBuilding, stmt.PDF.Status)
+
+	pdf, err := g.WaitForPdfStatement(context.Background(), testAccountId, stmt.PDF.ID, gomono.PollOptions{Interval: time.Millisecond})
+	assert.Nil(t, err)
+	assert.True(t, pdf.Built())
+	assert.Equal(t, 3, s.Calls("PdfStatementJobStatus"))
+
+	var buf bytes.Buffer
+	_, err = g.DownloadStatement(context.Background(), pdf, &buf)
+	assert.Nil(t, err)
+	assert.Equal(t, pdfBody, buf.String())
+
+	s.FailStatements(testAccountId)
+	stmt, _ = g.Statement(testAccountId, "", "pdf")
+	_, err = g.WaitForPdfStatement(context.Background(), testAccountId, stmt.PDF.ID, gomono.PollOptions{Interval: time.Millisecond})
+	assert.True(t, errors.Is(err, gomono.ErrStatementFailed))
+}
+
+func TestServer_ReauthAndFailures(t *testing.T) {
+	s, g := newTestServer(t)
+	defer s.Close()
+
+	s.RequireReauth(testAccountId)
+	_, err := g.Information(testAccountId)
+	assert.True(t, errors.Is(err, gomono.ErrReauthRequired))
+
+	sync, err := g.Sync(testAccountId)
+	assert.Nil(t, err)
+	assert.True(t, sync.ReauthorisationRequired())
+	assert.Equal(t, "reauth-"+testAccountId, sync.Token)
+
+	reauth, err := g.Reauthorise(testAccountId)
+	assert.Nil(t, err)
+	assert.Equal(t, sync.Token, reauth.Token)
+
+	s.CompleteReauth(testAccountId)
+	sync, err = g.Sync(testAccountId)
+	assert.Nil(t, err)
+	assert.False(t, sync.ReauthorisationRequired())
+
+	s.Fail("Income", 1, 503, `{"message": "Service unavailable"}`)
+	_, err = g.Income(testAccountId)
+	assert.True(t, errors.Is(err, gomono.ErrServer))
+	_, err = g.Income(testAccountId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, s.Calls("Income"))
+}
@@ -0,0 +1,185 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+//ErrPageLimitReached is returned once an iterator has fetched its maximum number of pages while more are available
+var ErrPageLimitReached = errors.New("gomono: transactions page limit reached")
+
+//TransactionsIterator walks through every page of an account's transactions by following Paging.Next.
+//
+//	it := gm.TransactionsIterator(id, time.Time{}, time.Time{}, "", "", 0)
+//	for it.Next() {
+//		tnx := it.Transaction()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type TransactionsIterator struct {
+	g        *gomono
+	ctx      context.Context
+	id       string
+	query    TransactionsQuery
+	maxPages int
+
+	pages    int
+	nextPage int
+	data     []Transaction
+	index    int
+	current  Transaction
+	done     bool
+	err      error
+}
+
+//NewTransactionsIterator returns an iterator over the given transactions that reports err once they are read,
+//e.g. to stub Gomono.TransactionsIterator in tests. The zero TransactionsIterator has no transactions.
+func NewTransactionsIterator(transactions []Transaction, err error) *TransactionsIterator {
+	return &TransactionsIterator{data: transactions, done: true, err: err}
+}
+
+//TransactionsIterator - Iterates over all pages of Transactions. A maxPages of 0 fetches every page.
+func (g *gomono) TransactionsIterator(id string, start, end time.Time, narration, tnxType string, maxPages int) *TransactionsIterator {
+	return g.TransactionsIteratorContext(context.Background(), id, start, end, narration, tnxType, maxPages)
+}
+
+//TransactionsIteratorContext - TransactionsIterator with a context for cancellation and deadlines
+func (g *gomono) TransactionsIteratorContext(ctx context.Context, id string, start, end time.Time, narration, tnxType string, maxPages int) *TransactionsIterator {
+	it := &TransactionsIterator{
+		g:   g,
+		ctx: ctx,
+		id:  id,
+		query: TransactionsQuery{
+			Start:     start,
+			End:       end,
+			Narration: narration,
+			Type:      TransactionType(tnxType),
+			Paginate:  true,
+		},
+		maxPages: maxPages,
+	}
+
+	if id == "" {
+		it.err = errors.New("gomono: ID is required")
+	} else if err := it.query.Validate(); err != nil {
+		it.err = err
+	}
+	return it
+}
+
+//AllTransactions - Fetches every page of Transactions. A maxPages of 0 fetches every page.
+//
+//When maxPages is reached before the last page, the transactions fetched so far are returned with ErrPageLimitReached.
+func (g *gomono) AllTransactions(id string, start, end time.Time, narration, tnxType string, maxPages int) ([]Transaction, error) {
+	return g.AllTransactionsContext(context.Background(), id, start, end, narration, tnxType, maxPages)
+}
+
+//AllTransactionsContext - AllTransactions with a context for cancellation and deadlines
+func (g *gomono) AllTransactionsContext(ctx context.Context, id string, start, end time.Time, narration, tnxType string, maxPages int) ([]Transaction, error) {
+	it := g.TransactionsIteratorContext(ctx, id, start, end, narration, tnxType, maxPages)
+
+	var transactions []Transaction
+	for it.Next() {
+		transactions = append(transactions, it.Transaction())
+	}
+	return transactions, it.Err()
+}
+
+//Next advances to the next transaction, fetching the next page when needed. It returns false once
+//every page has been read or an error occurred.
+func (it *TransactionsIterator) Next() bool {
+	if it == nil {
+		return false
+	}
+
+	for {
+		if it.index < len(it.data) {
+			it.current = it.data[it.index]
+			it.index++
+			return true
+		}
+
+		if it.done || it.err != nil {
+			return false
+		}
+
+		it.fetch()
+	}
+}
+
+//Transaction returns the transaction the iterator is positioned on
+func (it *TransactionsIterator) Transaction() Transaction {
+	if it == nil {
+		return Transaction{}
+	}
+	return it.current
+}
+
+//Err returns the error that stopped the iteration, if any
+func (it *TransactionsIterator) Err() error {
+	if it == nil {
+		return nil
+	}
+	return it.err
+}
+
+func (it *TransactionsIterator) fetch() {
+	//iterators not made by a Gomono client only hold the transactions they were created with
+	if it.g == nil {
+		it.done = true
+		return
+	}
+
+	if it.maxPages > 0 && it.pages >= it.maxPages {
+		it.err = ErrPageLimitReached
+		return
+	}
+
+	params := it.query.values()
+	if it.nextPage > 0 {
+		params.Set("page", strconv.Itoa(it.nextPage))
+	}
+
+	r, err := it.g.transactions(it.ctx, it.id, params)
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	it.pages++
+	it.data = r.Data
+	it.index = 0
+
+	if r.Paging.Next == "" {
+		it.done = true
+		return
+	}
+
+	//Only the page number is taken from the Next link, so the secret key is never sent to a host other than ApiUrl
+	page := pageFromLink(r.Paging.Next)
+	if page <= r.Paging.Page || page <= it.nextPage {
+		it.err = errors.New("gomono: unable to follow transactions page link " + r.Paging.Next)
+		return
+	}
+	it.nextPage = page
+}
+
+func pageFromLink(link string) int {
+	u, err := url.Parse(link)
+	if err != nil {
+		return 0
+	}
+
+	page, err := strconv.Atoi(u.Query().Get("page"))
+	if err != nil {
+		return 0
+	}
+	return page
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+//Logger receives a structured entry for every HTTP call made to Mono. Arguments are alternating keys and values.
+//
+//*slog.Logger satisfies it as is. zap's SugaredLogger (Infow/Errorw) and logrus need a few lines of adapter.
+type Logger interface {
+	Info(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+//maxLoggedBodySize caps how much of a failed response body is logged
+const maxLoggedBodySize = 4096
+
+var (
+	//piiFields matches JSON string fields holding personal data, e.g. "bvn": "12345678901"
+	piiFields = regexp.MustCompile(`(?i)("(?:bvn|nin|phone\w*|email|account_?number)"\s*:\s*)"[^"]*"`)
+
+	//piiParams matches the same fields sent as query parameters
+	piiParams = regexp.MustCompile(`(?i)\b((?:bvn|nin|phone\w*|email|account_?number)=)[^&\s"]*`)
+
+	piiEmail  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	piiPhone  = regexp.MustCompile(`\+?\b234\d{10}\b`)
+	piiDigits = regexp.MustCompile(`\b\d{10,}\b`)
+)
+
+//Redact masks BVNs, NINs, phone numbers, emails and account numbers in URLs and request or response bodies.
+//
+//Known JSON fields and query parameters are masked whatever their value, and any remaining email address
+//or run of 10 or more digits is masked as well.
+func Redact(s string) string {
+	s = piiFields.ReplaceAllString(s, `${1}"[REDACTED]"`)
+	s = piiParams.ReplaceAllString(s, `${1}[REDACTED]`)
+	s = piiEmail.ReplaceAllString(s, "[REDACTED]")
+	s = piiPhone.ReplaceAllString(s, "[REDACTED]")
+	return piiDigits.ReplaceAllString(s, "[REDACTED]")
+}
+
+//logging is the innermost middleware, logging every attempt with PII redacted
+func logging(logger Logger) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+
+			ep, _ := EndpointFromContext(req.Context())
+			fields := []interface{}{
+				"endpoint", ep.Name,
+				"method", req.Method,
+				"url", Redact(req.URL.String()),
+				"latency", time.Since(start),
+			}
+
+			if err != nil {
+				logger.Error("gomono: request failed", append(fields, "error", Redact(err.Error()))...)
+				return resp, err
+			}
+
+			fields = append(fields, "status", resp.StatusCode)
+			if resp.StatusCode < 400 {
+				logger.Info("gomono: request completed", fields...)
+				return resp, err
+			}
+
+			//Failed responses are small, so the body is buffered for the log and handed back untouched
+			b, readErr := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+			if readErr != nil {
+				return resp, readErr
+			}
+
+			if len(b) > maxLoggedBodySize {
+				b = b[:maxLoggedBodySize]
+			}
+			fields = append(fields, "response_body", Redact(string(b)))
+
+			if req.GetBody != nil {
+				if body, err := req.GetBody(); err == nil {
+					rb, _ := ioutil.ReadAll(io.LimitReader(body, maxLoggedBodySize))
+					body.Close()
+					fields = append(fields, "request_body", Redact(string(rb)))
+				}
+			}
+
+			logger.Error("gomono: request failed", fields...)
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	//MetricsCollector records the calls made to Mono. Endpoints are reported by template
+	//(e.g. "/accounts/{id}/transactions"), never with raw account IDs.
+	//
+	//Implement it over your metrics library of choice, or use PrometheusCollector.
+	MetricsCollector interface {
+		//ObserveRequest is called once per HTTP attempt
+		ObserveRequest(m RequestMetrics)
+		//ObserveRetry is called every time a failed attempt is about to be retried
+		ObserveRetry(endpoint string)
+	}
+
+	RequestMetrics struct {
+		Endpoint string
+		Method   string
+		//StatusCode is 0 when no response was received
+		StatusCode int
+		//ErrorKind is empty for successful calls, see the ErrorKind constants
+		ErrorKind string
+		Duration  time.Duration
+		BytesRead int
+	}
+
+	//PrometheusCollector is a MetricsCollector that keeps its metrics in memory and serves them
+	//in the Prometheus text exposition format, without depending on the Prometheus client library.
+	//
+	//	metrics := gomono.NewPrometheusCollector()
+	//	cfg.Metrics = metrics
+	//	http.Handle("/metrics/gomono", metrics)
+	PrometheusCollector struct {
+		mu        sync.Mutex
+		buckets   []float64
+		requests  map[[3]string]uint64
+		errors    map[[2]string]uint64
+		retries   map[string]uint64
+		bytesRead map[string]uint64
+		durations map[string]*histogram
+	}
+
+	histogram struct {
+		counts []uint64
+		sum    float64
+		count  uint64
+	}
+)
+
+//Values of RequestMetrics.ErrorKind
+const (
+	ErrorKindMono      = "mono"
+	ErrorKindTransport = "transport"
+	ErrorKindDecode    = "decode"
+	ErrorKindCanceled  = "canceled"
+	ErrorKindTimeout   = "timeout"
+)
+
+//DefaultLatencyBuckets are the request duration histogram buckets, in seconds
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+func (g *gomono) observeRequest(ctx context.Context, method string, status int, err error, d time.Duration, n int) {
+	if g.metrics == nil {
+		return
+	}
+
+	ep, _ := EndpointFromContext(ctx)
+	g.metrics.ObserveRequest(RequestMetrics{
+		Endpoint:   ep.Template,
+		Method:     method,
+		StatusCode: status,
+		ErrorKind:  errorKind(err),
+		Duration:   d,
+		BytesRead:  n,
+	})
+}
+
+func (g *gomono) observeRetry(ctx context.Context) {
+	if g.metrics == nil {
+		return
+	}
+
+	ep, _ := EndpointFromContext(ctx)
+	g.metrics.ObserveRetry(ep.Template)
+}
+
+func errorKind(err error) string {
+	var monoErr Error
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &monoErr):
+		return ErrorKindMono
+	case errors.Is(err, context.Canceled):
+		return ErrorKindCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorKindTimeout
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return ErrorKindDecode
+	}
+	return ErrorKindTransport
+}
+
+//NewPrometheusCollector creates a collector using the given latency buckets in seconds, or DefaultLatencyBuckets
+func NewPrometheusCollector(buckets ...float64) *PrometheusCollector {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+
+	sorted := append([]float64{}, buckets...)
+	sort.Float64s(sorted)
+
+	return &PrometheusCollector{
+		buckets:   sorted,
+		requests:  make(map[[3]string]uint64),
+		errors:    make(map[[2]string]uint64),
+		retries:   make(map[string]uint64),
+		bytesRead: make(map[string]uint64),
+		durations: make(map[string]*histogram),
+	}
+}
+
+func (p *PrometheusCollector) ObserveRequest(m RequestMetrics) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests[[3]string{m.Endpoint, m.Method, strconv.Itoa(m.StatusCode)}]++
+	if m.ErrorKind != "" {
+		p.errors[[2]string{m.Endpoint, m.ErrorKind}]++
+	}
+	p.bytesRead[m.Endpoint] += uint64(m.BytesRead)
+
+	h, ok := p.durations[m.Endpoint]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(p.buckets))}
+		p.durations[m.Endpoint] = h
+	}
+
+	secs := m.Duration.Seconds()
+	for i, b := range p.buckets {
+		if secs <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += secs
+	h.count++
+}
+
+func (p *PrometheusCollector) ObserveRetry(endpoint string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retries[endpoint]++
+}
+
+//ServeHTTP serves the metrics in the Prometheus text exposition format
+func (p *PrometheusCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+//WriteTo writes the metrics in the Prometheus text exposition format
+func (p *PrometheusCollector) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var b bytes.Buffer
+
+	b.WriteString("# HELP gomono_requests_total HTTP requests made to Mono.\n# TYPE gomono_requests_total counter\n")
+	for _, k := range sortedKeys3(p.requests) {
+		fmt.Fprintf(&b, "gomono_requests_total{endpoint=%v,method=%v,status=%v} %v\n", label(k[0]), label(k[1]), label(k[2]), p.requests[k])
+	}
+
+	b.WriteString("# HELP gomono_request_errors_total Failed HTTP requests made to Mono by kind of error.\n# TYPE gomono_request_errors_total counter\n")
+	for _, k := range sortedKeys2(p.errors) {
+		fmt.Fprintf(&b, "gomono_request_errors_total{endpoint=%v,kind=%v} %v\n", label(k[0]), label(k[1]), p.errors[k])
+	}
+
+	b.WriteString("# HELP gomono_retries_total Retried HTTP requests made to Mono.\n# TYPE gomono_retries_total counter\n")
+	for _, k := range sortedKeys(p.retries) {
+		fmt.Fprintf(&b, "gomono_retries_total{endpoint=%v} %v\n", label(k), p.retries[k])
+	}
+
+	b.WriteString("# HELP gomono_response_bytes_total Bytes read from Mono responses.\n# TYPE gomono_response_bytes_total counter\n")
+	for _, k := range sortedKeys(p.bytesRead) {
+		fmt.Fprintf(&b, "gomono_response_bytes_total{endpoint=%v} %v\n", label(k), p.bytesRead[k])
+	}
+
+	b.WriteString("# HELP gomono_request_duration_seconds Duration of HTTP requests made to Mono.\n# TYPE gomono_request_duration_seconds histogram\n")
+	endpoints := make([]string, 0, len(p.durations))
+	for k := range p.durations {
+		endpoints = append(endpoints, k)
+	}
+	sort.Strings(endpoints)
+
+	for _, ep := range endpoints {
+		h := p.durations[ep]
+		for i, bucket := range p.buckets {
+			fmt.Fprintf(&b, "gomono_request_duration_seconds_bucket{endpoint=%v,le=%v} %v\n", label(ep), label(strconv.FormatFloat(bucket, 'g', -1, 64)), h.counts[i])
+		}
+		fmt.Fprintf(&b, "gomono_request_duration_seconds_bucket{endpoint=%v,le=\"+Inf\"} %v\n", label(ep), h.count)
+		fmt.Fprintf(&b, "gomono_request_duration_seconds_sum{endpoint=%v} %v\n", label(ep), strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "gomono_request_duration_seconds_count{endpoint=%v} %v\n", label(ep), h.count)
+	}
+
+	return b.WriteTo(w)
+}
+
+//label quotes a label value as the exposition format expects
+func label(v string) string {
+	v = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+	return `"` + v + `"`
+}
+
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedKeys2(m map[[2]string]uint64) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.Join(keys[i][:], "\x00") < strings.Join(keys[j][:], "\x00")
+	})
+	return keys
+}
+
+func sortedKeys3(m map[[3]string]uint64) [][3]string {
+	keys := make([][3]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.Join(keys[i][:], "\x00") < strings.Join(keys[j][:], "\x00")
+	})
+	return keys
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"context"
+	"net/http"
+)
+
+type (
+	//RoundTripFunc sends a request to Mono and returns its response
+	RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+	//Middleware wraps every HTTP call made to Mono, retries included. It may change the request before calling next
+	//(e.g. to add a correlation ID header) and observe or replace the response it returns.
+	//
+	//	func correlationID(next gomono.RoundTripFunc) gomono.RoundTripFunc {
+	//		return func(req *http.Request) (*http.Response, error) {
+	//			req.Header.Set("X-Correlation-ID", correlationIDFrom(req.Context()))
+	//			return next(req)
+	//		}
+	//	}
+	Middleware func(next RoundTripFunc) RoundTripFunc
+
+	//Endpoint identifies the Mono endpoint a request is made for. It is available to middleware through EndpointFromContext.
+	Endpoint struct {
+		//Name is the Gomono method making the call, e.g. "Transactions"
+		Name string
+		//Template is the endpoint path with its parameters left out, e.g. "/accounts/{id}/transactions"
+		Template string
+	}
+
+	endpointContextKey struct{}
+)
+
+//EndpointFromContext returns the endpoint a request is made for. Use it with the request's context inside middleware.
+func EndpointFromContext(ctx context.Context) (Endpoint, bool) {
+	ep, ok := ctx.Value(endpointContextKey{}).(Endpoint)
+	return ep, ok
+}
+
+func withEndpoint(ctx context.Context, ep Endpoint) context.Context {
+	return context.WithValue(ctx, endpointContextKey{}, ep)
+}
+
+//chain builds the RoundTripFunc calling the middleware in order, the first one being the outermost
+func chain(middleware []Middleware, final RoundTripFunc) RoundTripFunc {
+	rt := final
+	for i := len(middleware) - 1; i >= 0; i-- {
+		rt = middleware[i](rt)
+	}
+	return rt
+}
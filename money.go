@@ -0,0 +1,165 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+//CurrencyNGN is the currency Mono reports amounts in unless an account says otherwise
+const CurrencyNGN = "NGN"
+
+//ErrCurrencyMismatch is returned when combining amounts in different currencies
+var ErrCurrencyMismatch = errors.New("gomono: currency mismatch")
+
+//Money is an exact amount in minor units (kobo for NGN), the way Mono reports every balance and amount.
+//
+//Currency is only known for account balances (from Account.Currency). An empty Currency is compatible with any other.
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+func NewMoney(minor int64, currency string) Money {
+	return Money{Minor: minor, Currency: currency}
+}
+
+//Float64 returns the amount in minor units as a float64, the value the response fields held before Money was introduced
+func (m Money) Float64() float64 {
+	return float64(m.Minor)
+}
+
+//Major returns the amount in major units (naira for NGN). It may lose precision and is meant for display only.
+func (m Money) Major() float64 {
+	return float64(m.Minor) / 100
+}
+
+func (m Money) IsZero() bool {
+	return m.Minor == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Minor < 0
+}
+
+func (m Money) Neg() Money {
+	return Money{Minor: -m.Minor, Currency: m.Currency}
+}
+
+func (m Money) Abs() Money {
+	if m.Minor < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+//Mul multiplies the amount by n
+func (m Money) Mul(n int64) Money {
+	return Money{Minor: m.Minor * n, Currency: m.Currency}
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	currency, err := m.currencyWith(o)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Minor: m.Minor + o.Minor, Currency: currency}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	return m.Add(o.Neg())
+}
+
+//Cmp returns -1, 0 or 1 if m is less than, equal to or greater than o
+func (m Money) Cmp(o Money) (int, error) {
+	if _, err := m.currencyWith(o); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case m.Minor < o.Minor:
+		return -1, nil
+	case m.Minor > o.Minor:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (m Money) currencyWith(o Money) (string, error) {
+	switch {
+	case m.Currency == "":
+		return o.Currency, nil
+	case o.Currency == "" || strings.EqualFold(m.Currency, o.Currency):
+		return m.Currency, nil
+	}
+	return "", fmt.Errorf("%w: %v and %v", ErrCurrencyMismatch, m.Currency, o.Currency)
+}
+
+//String formats the amount in major units with thousands separators, e.g. ₦378,367.09.
+//Amounts without a currency are formatted as naira.
+func (m Money) String() string {
+	minor := uint64(m.Minor)
+	sign := ""
+	if m.Minor < 0 {
+		sign = "-"
+		minor = uint64(-(m.Minor + 1)) + 1
+	}
+
+	digits := strconv.FormatUint(minor/100, 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+
+	symbol := "₦"
+	if m.Currency != "" && !strings.EqualFold(m.Currency, CurrencyNGN) {
+		symbol = strings.ToUpper(m.Currency) + " "
+	}
+	return fmt.Sprintf("%v%v%v.%02d", sign, symbol, b.String(), minor%100)
+}
+
+//MarshalJSON writes the amount in minor units, the way Mono sends it
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(m.Minor, 10)), nil
+}
+
+//UnmarshalJSON reads an amount in minor units sent as a JSON number or numeric string.
+//Fractions of a minor unit are rounded to the nearest unit.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if string(b) == "null" {
+		return nil
+	}
+
+	s := string(b)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+
+	if s == "" {
+		m.Minor = 0
+		return nil
+	}
+
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		m.Minor = v
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) || math.Abs(f) >= 1<<53 {
+		return fmt.Errorf("gomono: invalid amount %v", string(b))
+	}
+	m.Minor = int64(math.Round(f))
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ErrInvalidQuery is wrapped by every TransactionsQuery validation error
+var ErrInvalidQuery = errors.New("gomono: invalid transactions query")
+
+//TransactionType filters transactions by direction
+type TransactionType string
+
+const (
+	TransactionTypeCredit TransactionType = "credit"
+	TransactionTypeDebit  TransactionType = "debit"
+)
+
+//Valid reports whether t is a transaction type Mono accepts, ignoring case
+func (t TransactionType) Valid() bool {
+	t = t.normalize()
+	return t == TransactionTypeCredit || t == TransactionTypeDebit
+}
+
+//normalize lowercases t, the way Mono expects it
+func (t TransactionType) normalize() TransactionType {
+	return TransactionType(strings.ToLower(string(t)))
+}
+
+//TransactionsQuery holds the filters of a Transactions call. Zero values leave a filter out.
+//
+//	q := gomono.TransactionsQuery{
+//		Start:    time.Now().AddDate(0, -3, 0),
+//		End:      time.Now(),
+//		Type:     gomono.TransactionTypeDebit,
+//		Paginate: true,
+//		Page:     2,
+//	}
+type TransactionsQuery struct {
+	Start     time.Time
+	End       time.Time
+	Narration string
+	Type      TransactionType
+	//Paginate asks Mono for a single page of results. Setting Page or Limit turns it on.
+	Paginate bool
+	Page     int
+	Limit    int
+}
+
+//Validate checks the query before it is sent to Mono. Errors wrap ErrInvalidQuery.
+func (q TransactionsQuery) Validate() error {
+	if err := validateDateRange(q.Start, q.End); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+
+	if q.Type != "" && !q.Type.Valid() {
+		return fmt.Errorf("%w: transaction type must be credit or debit, got %q", ErrInvalidQuery, q.Type)
+	}
+
+	if q.Page < 0 {
+		return fmt.Errorf("%w: page cannot be negative", ErrInvalidQuery)
+	}
+
+	if q.Limit < 0 {
+		return fmt.Errorf("%w: limit cannot be negative", ErrInvalidQuery)
+	}
+
+	return nil
+}
+
+func (q TransactionsQuery) values() url.Values {
+	params := url.Values{}
+	if !q.Start.IsZero() {
+		params.Add("start", formatDate(q.Start))
+	}
+
+	if !q.End.IsZero() {
+		params.Add("end", formatDate(q.End))
+	}
+
+	if q.Narration != "" {
+		params.Add("narration", q.Narration)
+	}
+
+	if q.Type != "" {
+		params.Add("type", string(q.Type.normalize()))
+	}
+
+	if q.Page > 0 {
+		params.Add("page", strconv.Itoa(q.Page))
+	}
+
+	if q.Limit > 0 {
+		params.Add("limit", strconv.Itoa(q.Limit))
+	}
+
+	params.Add("paginate", strconv.FormatBool(q.Paginate || q.Page > 0 || q.Limit > 0))
+	return params
+}
@@ -0,0 +1,215 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type (
+	//Limit allows Rate requests per second on average, with bursts of up to Burst requests.
+	//A zero Rate means no limit.
+	Limit struct {
+		Rate  float64
+		Burst int
+	}
+
+	//RateLimit throttles the calls made to Mono on the client side. Every attempt, retries included,
+	//waits for both the global limit and the limit of its endpoint.
+	//
+	//Calls also pause on their own when Mono answers 429 with a Retry-After header or reports
+	//no remaining quota through the X-RateLimit-Remaining and X-RateLimit-Reset headers.
+	RateLimit struct {
+		Global Limit
+		//PerEndpoint limits are keyed by Gomono method name, e.g. "Transactions". New rejects unknown names.
+		PerEndpoint map[string]Limit
+	}
+
+	rateLimiter struct {
+		global    *tokenBucket
+		endpoints map[string]*tokenBucket
+	}
+
+	tokenBucket struct {
+		mu          sync.Mutex
+		rate        float64
+		burst       float64
+		tokens      float64
+		last        time.Time
+		pausedUntil time.Time
+	}
+)
+
+func (r *RateLimit) validate() error {
+	if r == nil {
+		return nil
+	}
+
+	limits := []Limit{r.Global}
+	for name, l := range r.PerEndpoint {
+		if !knownEndpoint(name) {
+			return fmt.Errorf("gomono: Unknown Rate Limit Endpoint %q", name)
+		}
+		limits = append(limits, l)
+	}
+
+	for _, l := range limits {
+		if l.Rate < 0 || l.Burst < 0 {
+			return errors.New("gomono: Rate Limits Cannot Be Negative")
+		}
+	}
+	return nil
+}
+
+func newRateLimiter(cfg *RateLimit) *rateLimiter {
+	if cfg == nil {
+		return nil
+	}
+
+	l := &rateLimiter{
+		global:    newTokenBucket(cfg.Global),
+		endpoints: make(map[string]*tokenBucket),
+	}
+	for name, limit := range cfg.PerEndpoint {
+		l.endpoints[name] = newTokenBucket(limit)
+	}
+	return l
+}
+
+func newTokenBucket(l Limit) *tokenBucket {
+	burst := float64(l.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: l.Rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+//wait blocks until the request may be sent or ctx is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	if err := l.global.wait(ctx); err != nil {
+		return err
+	}
+
+	ep, _ := EndpointFromContext(ctx)
+	if b, ok := l.endpoints[ep.Name]; ok {
+		if err := b.wait(ctx); err != nil {
+			//nothing is sent, so the global token goes back for other calls
+			l.global.refund()
+			return err
+		}
+	}
+	return nil
+}
+
+//adapt pauses requests when Mono reports that the quota is used up
+func (l *rateLimiter) adapt(ctx context.Context, resp *http.Response) {
+	if l == nil {
+		return
+	}
+
+	var pause time.Duration
+	if resp.StatusCode == http.StatusTooManyRequests {
+		pause = parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset := parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset")); reset > pause {
+			pause = reset
+		}
+	}
+
+	if pause <= 0 {
+		return
+	}
+
+	//Mono's quotas are per app, so every endpoint backs off
+	l.global.pause(pause)
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		d := b.reserve()
+		if d <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+//reserve takes a token if one is available, otherwise it returns how long to wait before trying again
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+
+	if b.rate <= 0 {
+		return 0
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+//refund gives back a token taken by a request that was not sent
+func (b *tokenBucket) refund() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens++; b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+func (b *tokenBucket) pause(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+//parseRateLimitReset reads X-RateLimit-Reset given either as seconds to wait or as a unix timestamp
+func parseRateLimitReset(v string) time.Duration {
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 {
+		return 0
+	}
+
+	if n > 1e9 {
+		return time.Until(time.Unix(n, 0))
+	}
+	return time.Duration(n) * time.Second
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+//ErrUnknownTenant is returned by Registry when no client is registered for a tenant
+var ErrUnknownTenant = errors.New("gomono: unknown tenant")
+
+type (
+	//Registry holds a Gomono client per tenant, one for each Mono app and its secret key.
+	//
+	//Clients are built from a shared base Config, so they share its HTTP client and transport along with
+	//its retries, middleware, logger, tracer and metrics. Rate limits and circuit breakers apply per tenant.
+	//It is safe for concurrent use.
+	//
+	//	reg := gomono.NewRegistry(gomono.NewDefaultConfig(""))
+	//	reg.Register("payments", os.Getenv("MONO_PAYMENTS_KEY"))
+	//	reg.Register("lending", os.Getenv("MONO_LENDING_KEY"))
+	//
+	//	gm, err := reg.FromContext(gomono.WithTenant(ctx, "lending"))
+	Registry struct {
+		base Config
+
+		mu      sync.RWMutex
+		clients map[string]Gomono
+	}
+
+	tenantContextKey struct{}
+)
+
+//NewRegistry creates an empty Registry. The SecretKey and SecretKeyProvider of base are ignored.
+func NewRegistry(base Config) *Registry {
+	if base.HttpClient == nil {
+		base.HttpClient = NewDefaultConfig("").HttpClient
+	}
+	return &Registry{base: base, clients: make(map[string]Gomono)}
+}
+
+//Register adds a tenant, or rotates its secret key when it is already registered.
+//
+//Calls in flight keep using the previous key while calls made after Register returns use the new one.
+//The rate limits and circuit breakers of the tenant carry over to the new key.
+func (r *Registry) Register(tenant, secretKey string) error {
+	if tenant == "" {
+		return errors.New("gomono: Tenant Name Is Required")
+	}
+
+	cfg := r.base
+	cfg.SecretKey = secretKey
+	cfg.SecretKeyProvider = nil
+	client, err := New(cfg)
+	if err != nil {
+		return fmt.Errorf("gomono: tenant %v: %w", tenant, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if prev, ok := r.clients[tenant].(*gomono); ok {
+		g := client.(*gomono)
+		g.limiter = prev.limiter
+		g.breakers = prev.breakers
+	}
+	r.clients[tenant] = client
+	return nil
+}
+
+//Remove drops a tenant. Calls in flight are not affected.
+func (r *Registry) Remove(tenant string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.clients, tenant)
+}
+
+//Client returns the client of a tenant
+func (r *Registry) Client(tenant string) (Gomono, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	client, ok := r.clients[tenant]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTenant, tenant)
+	}
+	return client, nil
+}
+
+//FromContext returns the client of the tenant set on ctx with WithTenant
+func (r *Registry) FromContext(ctx context.Context) (Gomono, error) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("%w: no tenant in context", ErrUnknownTenant)
+	}
+	return r.Client(tenant)
+}
+
+//Tenants returns the registered tenants in alphabetical order
+func (r *Registry) Tenants() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tenants := make([]string, 0, len(r.clients))
+	for t := range r.clients {
+		tenants = append(tenants, t)
+	}
+	sort.Strings(tenants)
+	return tenants
+}
+
+//WithTenant returns a copy of ctx carrying the tenant, for Registry.FromContext
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+//TenantFromContext returns the tenant set on ctx with WithTenant
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenant, ok
+}
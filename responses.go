@@ -4,25 +4,51 @@
 //
 package gomono
 
+import "encoding/json"
+
+//StatementStatus is the build status of a PDF statement
+type StatementStatus string
+
+//Values of StatementResponsePdf.Status
+const (
+	StatementStatusBuilding StatementStatus = "BUILDING"
+	StatementStatusBuilt    StatementStatus = "BUILT"
+	StatementStatusComplete StatementStatus = "COMPLETE"
+	StatementStatusFailed   StatementStatus = "FAILED"
+)
+
+//Values of SyncResponse.Status and SyncResponse.Code
+const (
+	SyncStatusSuccessful   = "successful"
+	SyncStatusFailed       = "failed"
+	SyncCodeSuccessful     = "SYNC_SUCCESSFUL"
+	SyncCodeReauthRequired = "REAUTHORISATION_REQUIRED"
+)
+
 type (
 	InformationResponse struct {
 		Meta struct {
 			DataStatus string `json:"data_status"`
 		} `json:"meta"`
-		Account struct {
-			ID            string  `json:"_id"`
-			Name          string  `json:"name"`
-			Currency      string  `json:"currency"`
-			Type          string  `json:"type"`
-			AccountNumber string  `json:"accountNumber"`
-			Balance       float64 `json:"balance"`
-			BVN           string  `json:"bvn"`
-			Institution   struct {
-				Name     string `json:"name"`
-				BankCode string `json:"bankCode"`
-				Type     string `json:"type"`
-			} `json:"institution"`
-		}
+		Account Account `json:"account"`
+	}
+
+	Account struct {
+		ID            string         `json:"_id"`
+		Name          string         `json:"name"`
+		Currency      string         `json:"currency"`
+		Type          string         `json:"type"`
+		AccountNumber string         `json:"accountNumber"`
+		Balance       Money          `json:"balance"`
+		BVN           string         `json:"bvn"`
+		Institution   InstitutionRef `json:"institution"`
+	}
+
+	//InstitutionRef is the bank an account is held at
+	InstitutionRef struct {
+		Name     string `json:"name"`
+		BankCode string `json:"bankCode"`
+		Type     string `json:"type"`
 	}
 
 	StatementResponse struct {
@@ -32,20 +58,37 @@ type (
 
 	StatementResponseJson struct {
 		Meta struct{ Count int } `json:"meta"`
-		Data []struct {
-			ID        string  `json:"_id"`
-			Type      string  `json:"type"`
-			Date      string  `json:"date"`
-			Narration string  `json:"narration"`
-			Amount    float64 `json:"amount"`
-			Balance   float64 `json:"balance"`
-		}
+		Data []StatementEntry
+	}
+
+	StatementEntry struct {
+		ID        string `json:"_id"`
+		Type      string `json:"type"`
+		Date      Date   `json:"date"`
+		Narration string `json:"narration"`
+		Amount    Money  `json:"amount"`
+		Balance   Money  `json:"balance"`
 	}
 
 	StatementResponsePdf struct {
-		ID     string `json:"id"`
-		Status string `json:"status"`
-		Path   string `json:"path"`
+		ID     string          `json:"id"`
+		Status StatementStatus `json:"status"`
+		Path   string          `json:"path"`
+	}
+
+	SyncResponse struct {
+		Status     string `json:"status"`
+		HasNewData bool   `json:"hasNewData"`
+		Code       string `json:"code"`
+		Token      string `json:"token"`
+	}
+
+	ReauthoriseResponse struct {
+		Token string `json:"token"`
+	}
+
+	UnlinkResponse struct {
+		Message string `json:"message"`
 	}
 
 	TransactionsResponse struct {
@@ -55,28 +98,33 @@ type (
 			Previous string `json:"previous"`
 			Next     string `json:"next"`
 		}
-		Data []struct {
-			ID        string  `json:"_id"`
-			Amount    float64 `json:"amount"`
-			Date      string  `json:"date"`
-			Narration string  `json:"narration"`
-			Type      string  `json:"type"`
-			Category  string  `json:"category"`
-			Balance   float64 `json:"balance"`
-		}
+		Data []Transaction
+	}
+
+	Transaction struct {
+		ID        string `json:"_id"`
+		Amount    Money  `json:"amount"`
+		Date      Date   `json:"date"`
+		Narration string `json:"narration"`
+		Type      string `json:"type"`
+		Category  string `json:"category"`
+		Balance   Money  `json:"balance"`
 	}
 
 	TransactionByTypeResponse struct {
-		Total   float64 `json:"total"`
-		History []struct {
-			Amount float64 `json:"amount"`
-			Period string  `json:"period"`
-		}
+		Total   Money `json:"total"`
+		History []PeriodTotal
+	}
+
+	//PeriodTotal is the total credited or debited within a period (mm-yy)
+	PeriodTotal struct {
+		Amount Money  `json:"amount"`
+		Period string `json:"period"`
 	}
 
 	IncomeResponse struct {
 		Type       string  `json:"type"`
-		Amount     float64 `json:"amount"`
+		Amount     Money   `json:"amount"`
 		Employer   string  `json:"employer"`
 		Confidence float64 `json:"confidence"`
 	}
@@ -85,10 +133,10 @@ type (
 		FirstName          string `json:"firstName"`
 		MiddleName         string `json:"middleName"`
 		LastName           string `json:"lastName"`
-		DateOfBirth        string `json:"dateOfBirth"`
+		DateOfBirth        Date   `json:"dateOfBirth"`
 		PhoneNumber1       string `json:"phoneNumber1"`
 		PhoneNumber2       string `json:"phoneNumber2"`
-		RegistrationDate   string `json:"registrationDate"`
+		RegistrationDate   Date   `json:"registrationDate"`
 		Email              string `json:"email"`
 		Gender             string `json:"gender"`
 		LevelOfAccount     string `json:"levelOfAccount"`
@@ -122,3 +170,31 @@ type (
 		Products []string `json:"products"`
 	}
 )
+
+//Built reports whether the PDF statement is ready to be downloaded from Path
+func (s StatementResponsePdf) Built() bool {
+	return s.Status == StatementStatusBuilt || s.Status == StatementStatusComplete
+}
+
+//Failed reports whether Mono gave up building the PDF statement
+func (s StatementResponsePdf) Failed() bool {
+	return s.Status == StatementStatusFailed
+}
+
+//UnmarshalJSON decodes the account and tags its balance with the account currency
+func (a *Account) UnmarshalJSON(b []byte) error {
+	type account Account
+	if err := json.Unmarshal(b, (*account)(a)); err != nil {
+		return err
+	}
+
+	if a.Balance.Currency == "" {
+		a.Balance.Currency = a.Currency
+	}
+	return nil
+}
+
+//ReauthorisationRequired reports whether the account must be reauthorised before it can be synced
+func (s SyncResponse) ReauthorisationRequired() bool {
+	return s.Code == SyncCodeReauthRequired
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//RetryPolicy describes how failed calls to Mono are retried.
+//
+//Only idempotent calls (GET, HEAD, OPTIONS, PUT, DELETE) are retried unless RetryNonIdempotent is set,
+//so POST endpoints like ExchangeToken and LookupBVN make a single attempt by default.
+type RetryPolicy struct {
+	//MaxAttempts is the total number of attempts, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+
+	//BaseDelay is the wait before the first retry. It doubles on every subsequent retry.
+	BaseDelay time.Duration
+
+	//MaxDelay caps the wait between two attempts. A Retry-After header asking for a longer wait stops the retries.
+	MaxDelay time.Duration
+
+	//Jitter is the fraction (0 to 1) of every wait that is randomized to spread out concurrent retries.
+	Jitter float64
+
+	//RetryableStatusCodes are the HTTP status codes that trigger a retry. Network errors are always retried.
+	RetryableStatusCodes []int
+
+	//RetryNonIdempotent opts POST and PATCH calls into retries.
+	RetryNonIdempotent bool
+}
+
+//DefaultRetryPolicy retries transient failures (429, 502, 503, 504) up to 3 attempts.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:          3,
+		BaseDelay:            200 * time.Millisecond,
+		MaxDelay:             2 * time.Second,
+		Jitter:               0.2,
+		RetryableStatusCodes: []int{429, 502, 503, 504},
+	}
+}
+
+func (p RetryPolicy) validate() error {
+	if p.MaxAttempts < 0 {
+		return errors.New("gomono: Retry MaxAttempts Cannot Be Negative")
+	}
+
+	if p.BaseDelay < 0 || p.MaxDelay < 0 {
+		return errors.New("gomono: Retry Delays Cannot Be Negative")
+	}
+
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return errors.New("gomono: Retry Jitter Must Be Between 0 and 1")
+	}
+
+	return nil
+}
+
+func (p RetryPolicy) allowsMethod(method string) bool {
+	if p.MaxAttempts < 2 {
+		return false
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return p.RetryNonIdempotent
+}
+
+func (p RetryPolicy) retryableStatus(code int) bool {
+	for _, c := range p.RetryableStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+//delay returns how long to wait after the given failed attempt and whether a retry should happen at all
+func (p RetryPolicy) delay(attempt int, err error) (time.Duration, bool) {
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay == 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	if p.Jitter > 0 && d > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+
+	if e, ok := err.(Error); ok && e.RetryAfter > 0 {
+		if p.MaxDelay > 0 && e.RetryAfter > p.MaxDelay {
+			return 0, false
+		}
+
+		if e.RetryAfter > d {
+			d = e.RetryAfter
+		}
+	}
+
+	return d, true
+}
+
+//parseRetryAfter reads a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,176 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	//SecretKeyProvider supplies the secret key sent with every call, so keys can be rotated without restarting.
+	//
+	//When Mono rejects a key with a 401, Refresh is called and the call is retried once if the key changed.
+	SecretKeyProvider interface {
+		SecretKey(ctx context.Context) (string, error)
+		//Refresh drops any cached key so the next SecretKey call returns the current one
+		Refresh(ctx context.Context) error
+	}
+
+	staticKey string
+
+	envKey string
+
+	fileKey struct {
+		path string
+
+		mu      sync.Mutex
+		key     string
+		modTime time.Time
+		size    int64
+	}
+
+	cachedKey struct {
+		ttl   time.Duration
+		fetch func(ctx context.Context) (string, error)
+
+		mu      sync.Mutex
+		key     string
+		expires time.Time
+	}
+)
+
+//NewEnvKeyProvider reads the secret key from an environment variable on every call
+func NewEnvKeyProvider(name string) SecretKeyProvider {
+	return envKey(name)
+}
+
+//NewFileKeyProvider reads the secret key from a file, such as a mounted Kubernetes secret,
+//and reads it again whenever the file changes
+func NewFileKeyProvider(path string) SecretKeyProvider {
+	return &fileKey{path: path}
+}
+
+//NewCachedKeyProvider gets the secret key from fetch, e.g. a secrets manager, and caches it for ttl.
+//A zero ttl caches the key until Mono rejects it.
+func NewCachedKeyProvider(ttl time.Duration, fetch func(ctx context.Context) (string, error)) SecretKeyProvider {
+	return &cachedKey{ttl: ttl, fetch: fetch}
+}
+
+func (k staticKey) SecretKey(ctx context.Context) (string, error) {
+	return string(k), nil
+}
+
+func (k staticKey) Refresh(ctx context.Context) error {
+	return nil
+}
+
+func (k envKey) SecretKey(ctx context.Context) (string, error) {
+	key := strings.TrimSpace(os.Getenv(string(k)))
+	if key == "" {
+		return "", fmt.Errorf("gomono: environment variable %v is empty", string(k))
+	}
+	return key, nil
+}
+
+func (k envKey) Refresh(ctx context.Context) error {
+	return nil
+}
+
+func (k *fileKey) SecretKey(ctx context.Context) (string, error) {
+	info, err := os.Stat(k.path)
+	if err != nil {
+		return "", err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.key != "" && info.ModTime().Equal(k.modTime) && info.Size() == k.size {
+		return k.key, nil
+	}
+
+	b, err := ioutil.ReadFile(k.path)
+	if err != nil {
+		return "", err
+	}
+
+	key := strings.TrimSpace(string(b))
+	if key == "" {
+		return "", fmt.Errorf("gomono: secret key file %v is empty", k.path)
+	}
+
+	k.key, k.modTime, k.size = key, info.ModTime(), info.Size()
+	return key, nil
+}
+
+func (k *fileKey) Refresh(ctx context.Context) error {
+	k.mu.Lock()
+	k.key = ""
+	k.mu.Unlock()
+
+	_, err := k.SecretKey(ctx)
+	return err
+}
+
+func (k *cachedKey) SecretKey(ctx context.Context) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.key != "" && (k.ttl <= 0 || time.Now().Before(k.expires)) {
+		return k.key, nil
+	}
+	return k.load(ctx)
+}
+
+func (k *cachedKey) Refresh(ctx context.Context) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	_, err := k.load(ctx)
+	return err
+}
+
+func (k *cachedKey) load(ctx context.Context) (string, error) {
+	key, err := k.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if key == "" {
+		return "", fmt.Errorf("gomono: secret key provider returned an empty key")
+	}
+
+	k.key, k.expires = key, time.Now().Add(k.ttl)
+	return key, nil
+}
+
+//secretKey returns the key for the next call, making sure it belongs to the configured environment
+func (g *gomono) secretKey(ctx context.Context) (string, error) {
+	key, err := g.keys.SecretKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("gomono: secret key unavailable: %w", err)
+	}
+
+	if detected := environmentFromKey(key); g.environment != "" && detected != "" && detected != g.environment {
+		return "", fmt.Errorf("%w: %v key used for the %v environment", ErrEnvironmentMismatch, detected, g.environment)
+	}
+	return key, nil
+}
+
+//refreshSecretKey refreshes the key after Mono rejected it and reports whether a different key is now available
+func (g *gomono) refreshSecretKey(ctx context.Context, rejected string) bool {
+	if err := g.keys.Refresh(ctx); err != nil {
+		return false
+	}
+
+	key, err := g.keys.SecretKey(ctx)
+	return err == nil && key != rejected
+}
@@ -0,0 +1,221 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+//maxStatementSize caps the size of a downloaded PDF statement
+const maxStatementSize = 100 << 20
+
+//ErrStatementFailed is returned when Mono reports that a PDF statement could not be built
+var ErrStatementFailed = errors.New("gomono: pdf statement failed")
+
+//PollOptions controls how often WaitForPdfStatement checks the job status.
+//The wait starts at Interval and is multiplied by Multiplier after every check, up to MaxInterval.
+type PollOptions struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Multiplier  float64
+}
+
+//DefaultPollOptions checks every 2 seconds, backing off to every 10 seconds
+func DefaultPollOptions() PollOptions {
+	return PollOptions{
+		Interval:    2 * time.Second,
+		MaxInterval: 10 * time.Second,
+		Multiplier:  1.5,
+	}
+}
+
+//WaitForPdfStatement - Polls PdfStatementJobStatus until the statement is built, has failed or ctx is done.
+//
+//The final status is returned once built, so its Path can be downloaded. A failed job returns ErrStatementFailed.
+//Zero values in opts fall back to DefaultPollOptions.
+func (g *gomono) WaitForPdfStatement(ctx context.Context, id, jobId string, opts PollOptions) (*StatementResponsePdf, error) {
+	opts = opts.withDefaults()
+	interval := opts.Interval
+
+	for {
+		r, err := g.PdfStatementJobStatusContext(ctx, id, jobId)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case r.Built():
+			return r, nil
+		case r.Failed():
+			return r, fmt.Errorf("%w: job %v", ErrStatementFailed, jobId)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("gomono: waiting for statement job %v aborted: %w", jobId, ctx.Err())
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * opts.Multiplier)
+		if interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+}
+
+func (o PollOptions) withDefaults() PollOptions {
+	d := DefaultPollOptions()
+	if o.Interval <= 0 {
+		o.Interval = d.Interval
+	}
+
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = d.MaxInterval
+	}
+
+	if o.MaxInterval < o.Interval {
+		o.MaxInterval = o.Interval
+	}
+
+	if o.Multiplier < 1 {
+		o.Multiplier = 1
+	}
+	return o
+}
+
+//DownloadStatement - Streams a built PDF statement from its Path into w using the configured HTTP client.
+//
+//The download fails unless Mono serves a PDF, and when fewer bytes than the announced Content-Length arrive.
+//The secret key is only sent when Path points to the configured ApiUrl scheme and host. Like other calls, failed
+//downloads are retried under Config.Retry as long as nothing was written to w, and a rejected key is refreshed once.
+func (g *gomono) DownloadStatement(ctx context.Context, pdf *StatementResponsePdf, w io.Writer) (int64, error) {
+	if pdf == nil || pdf.Path == "" {
+		return 0, errors.New("gomono: statement Path is required")
+	}
+
+	if !pdf.Built() {
+		return 0, fmt.Errorf("gomono: statement %v is not built yet (status %v)", pdf.ID, pdf.Status)
+	}
+
+	ctx = withEndpoint(ctx, endpointDownloadStatement)
+	ctx, span := g.startSpan(ctx, endpointDownloadStatement, "GET", pdf.Path)
+	defer span.End()
+
+	var written int64
+	status, retries, err := g.requestWithRetries(ctx, "GET", pdf.Path, g.sendsKeyTo(pdf.Path), func(ctx context.Context, key string) (int, bool, int, error) {
+		status, retry, n, err := g.downloadStatement(ctx, key, pdf, w)
+		written += n
+		return status, retry, int(n), err
+	})
+	endSpan(span, status, retries, err)
+	return written, err
+}
+
+//sendsKeyTo reports whether the secret key may be sent to download a statement from path. Only paths on the
+//ApiUrl scheme and host get it, so the key is neither sent to another host nor downgraded to plain HTTP.
+func (g *gomono) sendsKeyTo(path string) bool {
+	api, apiErr := url.Parse(g.apiUrl)
+	u, err := url.Parse(path)
+	return apiErr == nil && err == nil && u.Scheme == api.Scheme && u.Host == api.Host
+}
+
+//downloadStatement makes a single attempt at downloading the statement into w. Failed attempts may only be
+//retried when nothing was written to w yet.
+func (g *gomono) downloadStatement(ctx context.Context, key string, pdf *StatementResponsePdf, w io.Writer) (int, bool, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pdf.Path, nil)
+	if err != nil {
+		return 0, false, 0, err
+	}
+
+	if key != "" {
+		req.Header.Set("mono-sec-key", key)
+	}
+	req.Header.Set("Accept", "application/pdf")
+	req.Header.Set("X-Client-Lib", "GoMono | v1 | github.com/jcobhams/gomono")
+
+	resp, err := g.roundTrip(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return 0, false, 0, fmt.Errorf("gomono: request to %v aborted: %w", req.URL.String(), ctxErr)
+		}
+		return 0, true, 0, err
+	}
+	defer resp.Body.Close()
+	g.limiter.adapt(ctx, resp)
+
+	if resp.StatusCode != 200 {
+		b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		return resp.StatusCode, g.retry.retryableStatus(resp.StatusCode), 0, newError(req.URL.String(), resp, b)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if contentType != "application/pdf" && contentType != "application/octet-stream" {
+		return resp.StatusCode, false, 0, fmt.Errorf("gomono: unexpected statement content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	if resp.ContentLength > maxStatementSize {
+		return resp.StatusCode, false, 0, fmt.Errorf("gomono: statement of %v bytes exceeds the %v bytes limit", resp.ContentLength, maxStatementSize)
+	}
+
+	n, err := io.Copy(w, io.LimitReader(resp.Body, maxStatementSize+1))
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return resp.StatusCode, false, n, fmt.Errorf("gomono: request to %v aborted: %w", req.URL.String(), ctxErr)
+		}
+		return resp.StatusCode, false, n, err
+	}
+
+	if n > maxStatementSize {
+		return resp.StatusCode, false, n, fmt.Errorf("gomono: statement exceeds the %v bytes limit", maxStatementSize)
+	}
+
+	if resp.ContentLength >= 0 && n != resp.ContentLength {
+		return resp.StatusCode, false, n, fmt.Errorf("gomono: statement download incomplete, got %v of %v bytes", n, resp.ContentLength)
+	}
+
+	return resp.StatusCode, false, n, nil
+}
+
+//DownloadStatementToFile - Downloads a built PDF statement to path.
+//
+//The file is written next to path under a temporary name and renamed into place once complete,
+//so path never holds a partial statement.
+func (g *gomono) DownloadStatementToFile(ctx context.Context, pdf *StatementResponsePdf, path string) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	//Cleanup is a no-op once the file has been renamed
+	defer os.Remove(tmp.Name())
+
+	if _, err := g.DownloadStatement(ctx, pdf, tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package gomono
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+)
+
+type (
+	//Tracer starts a span for every Gomono call. Its shape follows OpenTelemetry's, so an adapter over
+	//an otel trace.Tracer only has to forward the calls.
+	//
+	//The context returned by Start is the one the HTTP requests are made with, so middleware and
+	//transports can propagate the span.
+	Tracer interface {
+		Start(ctx context.Context, spanName string) (context.Context, Span)
+	}
+
+	Span interface {
+		SetAttribute(key string, value interface{})
+		RecordError(err error)
+		End()
+	}
+
+	noopSpan struct{}
+)
+
+//Span attributes set by gomono
+const (
+	AttributeEndpoint      = "mono.endpoint"
+	AttributeAccountIDHash = "mono.account_id_hash"
+	AttributeHTTPMethod    = "http.method"
+	AttributeHTTPStatus    = "http.status_code"
+	AttributeRetryCount    = "gomono.retry_count"
+)
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+func (noopSpan) RecordError(err error)                      {}
+func (noopSpan) End()                                       {}
+
+//startSpan starts the span of a Gomono call named after its endpoint, e.g. "gomono.Transactions"
+func (g *gomono) startSpan(ctx context.Context, ep Endpoint, method, rawUrl string) (context.Context, Span) {
+	if g.tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	ctx, span := g.tracer.Start(ctx, "gomono."+ep.Name)
+	span.SetAttribute(AttributeEndpoint, ep.Template)
+	span.SetAttribute(AttributeHTTPMethod, method)
+
+	if u, err := url.Parse(rawUrl); err == nil {
+		if id := ep.pathParam(u.Path, "id"); id != "" {
+			span.SetAttribute(AttributeAccountIDHash, hashAccountID(id))
+		}
+	}
+	return ctx, span
+}
+
+func endSpan(span Span, status, retries int, err error) {
+	if status > 0 {
+		span.SetAttribute(AttributeHTTPStatus, status)
+	}
+	span.SetAttribute(AttributeRetryCount, retries)
+
+	if err != nil {
+		span.RecordError(err)
+	}
+}
+
+//pathParam returns the value of the {name} parameter of the endpoint template within path.
+//Segments are matched from the end so an ApiUrl with a base path still lines up.
+func (e Endpoint) pathParam(path, name string) string {
+	tmpl := strings.Split(strings.Trim(e.Template, "/"), "/")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < len(tmpl) {
+		return ""
+	}
+
+	segments = segments[len(segments)-len(tmpl):]
+	for i, t := range tmpl {
+		if t == "{"+name+"}" {
+			return segments[i]
+		}
+	}
+	return ""
+}
+
+//hashAccountID identifies an account in traces without exposing its ID
+func hashAccountID(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,195 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+//Package webhook receives the events Mono pushes to an app's webhook URL.
+//
+//	h := webhook.NewHandler("YOUR_WEBHOOK_SECRET")
+//	h.OnAccountUpdated(func(ctx context.Context, e webhook.AccountUpdatedEvent) error {
+//		//e.Meta.DataStatus, e.Account.ID ...
+//		return nil
+//	})
+//	http.Handle("/mono/webhook", h)
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"github.com/jcobhams/gomono"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+//HeaderSecret is the header Mono sets to the app's webhook secret on every event
+const HeaderSecret = "mono-webhook-secret"
+
+//Event types sent by Mono
+const (
+	EventAccountConnected        = "mono.events.account_connected"
+	EventAccountUpdated          = "mono.events.account_updated"
+	EventReauthorisationRequired = "mono.events.reauthorisation_required"
+	EventJobCompleted            = "mono.events.job_completed"
+)
+
+//maxBodySize caps how much of a webhook request body is read
+const maxBodySize = 1 << 20
+
+type (
+	//Event is the envelope of every webhook. Data holds the event specific payload.
+	Event struct {
+		Type string          `json:"event"`
+		Data json.RawMessage `json:"data"`
+	}
+
+	AccountConnectedEvent struct {
+		ID string `json:"id"`
+	}
+
+	//AccountUpdatedEvent carries the same account details and data status as gomono.Information
+	AccountUpdatedEvent struct {
+		gomono.InformationResponse
+	}
+
+	ReauthorisationRequiredEvent struct {
+		Account struct {
+			ID string `json:"_id"`
+		} `json:"account"`
+	}
+
+	//JobCompletedEvent reports a finished PDF statement job
+	JobCompletedEvent struct {
+		Account string                      `json:"account"`
+		Job     gomono.StatementResponsePdf `json:"job"`
+	}
+
+	//Handler is an http.Handler that verifies webhook requests and dispatches them to the registered callbacks.
+	//
+	//Requests without the right secret are rejected with 401 and callbacks that return an error cause a 500,
+	//so Mono retries the delivery. Events without a registered callback are acknowledged and dropped.
+	Handler struct {
+		secret    string
+		mu        sync.RWMutex
+		callbacks map[string][]func(ctx context.Context, e Event) error
+	}
+)
+
+func NewHandler(secret string) *Handler {
+	return &Handler{
+		secret:    secret,
+		callbacks: make(map[string][]func(ctx context.Context, e Event) error),
+	}
+}
+
+//OnEvent registers a callback receiving the raw event for the given event type
+func (h *Handler) OnEvent(eventType string, fn func(ctx context.Context, e Event) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.callbacks[eventType] = append(h.callbacks[eventType], fn)
+}
+
+func (h *Handler) OnAccountConnected(fn func(ctx context.Context, e AccountConnectedEvent) error) {
+	h.OnEvent(EventAccountConnected, func(ctx context.Context, e Event) error {
+		var data AccountConnectedEvent
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		return fn(ctx, data)
+	})
+}
+
+func (h *Handler) OnAccountUpdated(fn func(ctx context.Context, e AccountUpdatedEvent) error) {
+	h.OnEvent(EventAccountUpdated, func(ctx context.Context, e Event) error {
+		var data AccountUpdatedEvent
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		return fn(ctx, data)
+	})
+}
+
+func (h *Handler) OnReauthorisationRequired(fn func(ctx context.Context, e ReauthorisationRequiredEvent) error) {
+	h.OnEvent(EventReauthorisationRequired, func(ctx context.Context, e Event) error {
+		var data ReauthorisationRequiredEvent
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		return fn(ctx, data)
+	})
+}
+
+func (h *Handler) OnJobCompleted(fn func(ctx context.Context, e JobCompletedEvent) error) {
+	h.OnEvent(EventJobCompleted, func(ctx context.Context, e Event) error {
+		var data JobCompletedEvent
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return err
+		}
+		return fn(ctx, data)
+	})
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.verify(r.Header.Get(HeaderSecret)) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	e, err := decode(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.dispatch(r.Context(), e); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) verify(secret string) bool {
+	if h.secret == "" || secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(h.secret)) == 1
+}
+
+func (h *Handler) dispatch(ctx context.Context, e Event) error {
+	h.mu.RLock()
+	callbacks := h.callbacks[e.Type]
+	h.mu.RUnlock()
+
+	for _, fn := range callbacks {
+		if err := fn(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decode(body io.Reader) (Event, error) {
+	var e Event
+
+	b, err := ioutil.ReadAll(io.LimitReader(body, maxBodySize))
+	if err != nil {
+		return e, err
+	}
+
+	if err := json.Unmarshal(b, &e); err != nil {
+		return e, errors.New("webhook: malformed event payload")
+	}
+
+	if e.Type == "" {
+		return e, errors.New("webhook: missing event type")
+	}
+	return e, nil
+}
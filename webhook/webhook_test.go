@@ -0,0 +1,87 @@
+// Copyright 2020 Joseph Cobhams. All rights reserved.
+// Use of this source code is governed by the MIT license
+// that can be found in the LICENSE.md file.
+//
+package webhook
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var testSecret = "TEST_WEBHOOK_SECRET"
+
+func send(h http.Handler, secret, body string) int {
+	req := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+	req.Header.Set(HeaderSecret, secret)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestHandler_Verify(t *testing.T) {
+	h := NewHandler(testSecret)
+	body := `{"event": "mono.events.account_connected", "data": {"id": "5fc68b964bdcbe4eb164e852"}}`
+
+	assert.Equal(t, http.StatusUnauthorized, send(h, "", body))
+	assert.Equal(t, http.StatusUnauthorized, send(h, "WRONG_SECRET", body))
+	assert.Equal(t, http.StatusOK, send(h, testSecret, body))
+	assert.Equal(t, http.StatusBadRequest, send(h, testSecret, `{"data": {}}`))
+	assert.Equal(t, http.StatusBadRequest, send(h, testSecret, `not json`))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/webhook", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestHandler_Dispatch(t *testing.T) {
+	h := NewHandler(testSecret)
+
+	var connected AccountConnectedEvent
+	h.OnAccountConnected(func(ctx context.Context, e AccountConnectedEvent) error {
+		connected = e
+		return nil
+	})
+
+	var updated AccountUpdatedEvent
+	h.OnAccountUpdated(func(ctx context.Context, e AccountUpdatedEvent) error {
+		updated = e
+		return nil
+	})
+
+	var reauth ReauthorisationRequiredEvent
+	h.OnReauthorisationRequired(func(ctx context.Context, e ReauthorisationRequiredEvent) error {
+		reauth = e
+		return nil
+	})
+
+	var job JobCompletedEvent
+	h.OnJobCompleted(func(ctx context.Context, e JobCompletedEvent) error {
+		job = e
+		return errors.New("storage unavailable")
+	})
+
+	assert.Equal(t, http.StatusOK, send(h, testSecret, `{"event": "mono.events.account_connected", "data": {"id": "5fc68b964bdcbe4eb164e852"}}`))
+	assert.Equal(t, "5fc68b964bdcbe4eb164e852", connected.ID)
+
+	assert.Equal(t, http.StatusOK, send(h, testSecret, `{"event": "mono.events.account_updated", "data": {
+		"meta": {"data_status": "AVAILABLE"},
+		"account": {"_id": "5fc68b964bdcbe4eb164e852", "name": "IDORENYIN OBONG OBONG", "institution": {"name": "Access Bank", "bankCode": "044"}}
+	}}`))
+	assert.Equal(t, "AVAILABLE", updated.Meta.DataStatus)
+	assert.Equal(t, "5fc68b964bdcbe4eb164e852", updated.Account.ID)
+	assert.Equal(t, "044", updated.Account.Institution.BankCode)
+
+	assert.Equal(t, http.StatusOK, send(h, testSecret, `{"event": "mono.events.reauthorisation_required", "data": {"account": {"_id": "5fc68b964bdcbe4eb164e852"}}}`))
+	assert.Equal(t, "5fc68b964bdcbe4eb164e852", reauth.Account.ID)
+
+	assert.Equal(t, http.StatusInternalServerError, send(h, testSecret, `{"event": "mono.events.job_completed", "data": {"account": "5fc68b964bdcbe4eb164e852", "job": {"id": "MvRh2vWwv5CGafudTivY", "status": "BUILT"}}}`))
+	assert.Equal(t, "MvRh2vWwv5CGafudTivY", job.Job.ID)
+
+	assert.Equal(t, http.StatusOK, send(h, testSecret, `{"event": "mono.events.unknown", "data": {}}`))
+}